@@ -1,7 +1,9 @@
 package ghapi
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -14,6 +16,43 @@ import (
 type APIInfo struct {
 	BaseURL     string
 	OAuth2Token string
+	// TokenSource supplies the token for each request. If set, it's used instead of OAuth2Token.
+	TokenSource TokenSource
+	// Authenticator adds credentials to each request. If set, it's used instead of TokenSource and OAuth2Token.
+	Authenticator Authenticator
+	// HTTPClient is the client used to make requests. If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+	// Middleware wraps the HTTPClient's transport for every request. The first Middleware is the outermost.
+	Middleware []Middleware
+	// Retry is the policy for retrying failed requests. If nil, requests are not retried.
+	Retry *RetryPolicy
+	// Redactor masks secrets in the bodies and URL of an ErrHTTPError, and the URL of a *url.Error. If nil,
+	// DefaultRedactor is used.
+	Redactor *Redactor
+	// Cache stores GET responses for conditional requests. If nil, responses are not cached.
+	Cache Cache
+
+	rateLimits *rateLimitState
+	cacheStats *cacheStats
+}
+
+// Option configures an APIInfo. Options are passed to NewGitHubAPI and the package level API functions.
+type Option func(*APIInfo)
+
+// WithHTTPClient sets the *http.Client used to make requests. Use this to set timeouts, proxies or custom TLS
+// configuration (for example, root CAs for GitHub Enterprise).
+func WithHTTPClient(client *http.Client) Option {
+	return func(apiInfo *APIInfo) {
+		apiInfo.HTTPClient = client
+	}
+}
+
+// WithMiddleware appends Middleware to the chain every request passes through. The first Middleware is the
+// outermost.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(apiInfo *APIInfo) {
+		apiInfo.Middleware = append(apiInfo.Middleware, middleware...)
+	}
 }
 
 // RepositoryInfo contains APIInfo, the repository owner, and the repository name.
@@ -35,6 +74,11 @@ type GitHubAPI struct {
 	Repository   RepositoryAPI
 	Contents     ContentsAPI
 	Refs         RefsAPI
+	Milestones   MilestonesAPI
+	Search       SearchAPI
+	Hooks        HooksAPI
+	// OrganizationHooks manages the hooks of the owner's organization, which requires the admin:org_hook scope.
+	OrganizationHooks HooksAPI
 }
 
 // IssueAPI is used to get information about a repository's issues. Note Pull Requests are treated as issues in some
@@ -84,6 +128,16 @@ type RefsAPI struct {
 	RepositoryInfo
 }
 
+// MilestonesAPI is used to get, create, update and delete a repository's milestones.
+type MilestonesAPI struct {
+	RepositoryInfo
+}
+
+// SearchAPI is used to search issues, pull requests, code, commits, repositories and users.
+type SearchAPI struct {
+	APIInfo
+}
+
 // AuthenticatedUser contains information about the current authenticated user.
 type AuthenticatedUser struct {
 	Login             string    `json:"login"`
@@ -130,8 +184,8 @@ type AuthenticatedUser struct {
 }
 
 // NewGitHubAPI returns a new GitHubAPI using the specified repository and authentication information.
-func NewGitHubAPI(baseURL, owner, repository, authToken string) GitHubAPI {
-	apiInfo := APIInfo{BaseURL: baseURL, OAuth2Token: authToken}
+func NewGitHubAPI(baseURL, owner, repository, authToken string, opts ...Option) GitHubAPI {
+	apiInfo := newAPIInfo(baseURL, authToken, opts)
 
 	gitHubAPI := GitHubAPI{APIInfo: apiInfo}
 
@@ -150,15 +204,24 @@ func NewGitHubAPI(baseURL, owner, repository, authToken string) GitHubAPI {
 	gitHubAPI.Repository = RepositoryAPI{RepositoryInfo: repositoryInfo}
 	gitHubAPI.Contents = ContentsAPI{RepositoryInfo: repositoryInfo}
 	gitHubAPI.Refs = RefsAPI{RepositoryInfo: repositoryInfo}
+	gitHubAPI.Milestones = MilestonesAPI{RepositoryInfo: repositoryInfo}
+	gitHubAPI.Search = SearchAPI{APIInfo: apiInfo}
+	gitHubAPI.Hooks = HooksAPI{RepositoryInfo: repositoryInfo}
+	gitHubAPI.OrganizationHooks = HooksAPI{RepositoryInfo: repositoryInfo, organization: true}
 
 	return gitHubAPI
 }
 
 // GetUser returns the current authenticated user.
-func GetUser(baseURL, authToken string) (*AuthenticatedUser, error) {
-	apiInfo := APIInfo{BaseURL: baseURL, OAuth2Token: authToken}
+func GetUser(baseURL, authToken string, opts ...Option) (*AuthenticatedUser, error) {
+	return GetUserContext(context.Background(), baseURL, authToken, opts...)
+}
+
+// GetUserContext is like GetUser but uses the provided context.
+func GetUserContext(ctx context.Context, baseURL, authToken string, opts ...Option) (*AuthenticatedUser, error) {
+	apiInfo := newAPIInfo(baseURL, authToken, opts)
 	url := apiInfo.addBaseURL("/user")
-	resp, err := apiInfo.httpGet(url)
+	resp, err := apiInfo.httpGet(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -175,10 +238,15 @@ func GetUser(baseURL, authToken string) (*AuthenticatedUser, error) {
 }
 
 // GetOAuthScopes returns the current authenticated user's OAuth scopes.
-func GetOAuthScopes(baseURL, authToken string) ([]string, error) {
-	apiInfo := APIInfo{BaseURL: baseURL, OAuth2Token: authToken}
+func GetOAuthScopes(baseURL, authToken string, opts ...Option) ([]string, error) {
+	return GetOAuthScopesContext(context.Background(), baseURL, authToken, opts...)
+}
+
+// GetOAuthScopesContext is like GetOAuthScopes but uses the provided context.
+func GetOAuthScopesContext(ctx context.Context, baseURL, authToken string, opts ...Option) ([]string, error) {
+	apiInfo := newAPIInfo(baseURL, authToken, opts)
 	url := apiInfo.addBaseURL("/user")
-	resp, err := apiInfo.httpGet(url)
+	resp, err := apiInfo.httpGet(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -217,10 +285,15 @@ type OrgSummary struct {
 // GetOrganizations returns Organization summary information from /organizations.
 //
 // See https://developer.github.com/v3/orgs/#list-all-organizations.
-func GetOrganizations(baseURL, authToken string, since int) ([]OrgSummary, error) {
-	apiInfo := APIInfo{BaseURL: baseURL, OAuth2Token: authToken}
+func GetOrganizations(baseURL, authToken string, since int, opts ...Option) ([]OrgSummary, error) {
+	return GetOrganizationsContext(context.Background(), baseURL, authToken, since, opts...)
+}
+
+// GetOrganizationsContext is like GetOrganizations but uses the provided context.
+func GetOrganizationsContext(ctx context.Context, baseURL, authToken string, since int, opts ...Option) ([]OrgSummary, error) {
+	apiInfo := newAPIInfo(baseURL, authToken, opts)
 	url := apiInfo.addBaseURL(fmt.Sprintf("/organizations?since=%d", since))
-	resp, err := apiInfo.httpGet(url)
+	resp, err := apiInfo.httpGet(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -241,20 +314,44 @@ func Is404(err error) bool {
 	return IsHTTPError(err, 404)
 }
 
-// IsHTTPError returns true if the error is an HTTP error with the specified status code.
+// IsNotFound returns true if the error is an HTTP 404.
+func IsNotFound(err error) bool {
+	return IsHTTPError(err, 404)
+}
+
+// IsUnauthorized returns true if the error is an HTTP 401, returned when credentials are missing or invalid.
+func IsUnauthorized(err error) bool {
+	return IsHTTPError(err, 401)
+}
+
+// IsConflict returns true if the error is an HTTP 409, returned for example when a merge conflicts.
+func IsConflict(err error) bool {
+	return IsHTTPError(err, 409)
+}
+
+// IsValidationError returns true if the error is an HTTP 422, returned when the request body is invalid or, for
+// example, a ref already exists. The ErrorResponse of the *ErrHTTPError describes the problem.
+func IsValidationError(err error) bool {
+	return IsHTTPError(err, 422)
+}
+
+// IsHTTPError returns true if the error is, or wraps, an HTTP error with the specified status code.
 func IsHTTPError(err error, statusCode int) bool {
-	if err == nil {
-		return false
-	}
-	switch val := err.(type) {
-	case *ErrHTTPError:
-		if val.StatusCode == statusCode {
-			return true
-		}
+	var httpErr *ErrHTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == statusCode
 	}
 	return false
 }
 
+func newAPIInfo(baseURL, authToken string, opts []Option) APIInfo {
+	apiInfo := APIInfo{BaseURL: baseURL, OAuth2Token: authToken, rateLimits: &rateLimitState{}, cacheStats: &cacheStats{}}
+	for _, opt := range opts {
+		opt(&apiInfo)
+	}
+	return apiInfo
+}
+
 func (apiInfo *APIInfo) addBaseURL(url string) string {
 	return apiInfo.BaseURL + url
 }
@@ -265,79 +362,130 @@ func (apiInfo *RepositoryInfo) getURL(url string) string {
 	return apiInfo.addBaseURL(url)
 }
 
-func (apiInfo *APIInfo) getHTTPRequest(method, url string, body *string) (*http.Request, error) {
+func (apiInfo *APIInfo) getHTTPRequest(ctx context.Context, method, url string, body *string) (*http.Request, error) {
 	var bodyReader io.Reader
 	if body != nil {
 		bodyReader = strings.NewReader(*body)
 	}
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Add("Accept", "application/vnd.github.v3+json")
 	req.Header.Add("Content-Type", "application/json")
-	if apiInfo.OAuth2Token != "" {
-		req.Header.Add("Authorization", fmt.Sprintf("token %s", apiInfo.OAuth2Token))
+	if auth := apiInfo.authenticator(); auth != nil {
+		if err = auth.Authenticate(req); err != nil {
+			return nil, err
+		}
 	}
 
 	return req, nil
 }
 
-func (apiInfo *APIInfo) doHTTPRequest(method, url string, body *string, acceptHeader string) (*http.Response, error) {
-	req, err := apiInfo.getHTTPRequest(method, url, body)
-	if err != nil {
-		return nil, err
-	}
+func (apiInfo *APIInfo) doHTTPRequest(ctx context.Context, method, url string, body *string, acceptHeader string) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		// the request is rebuilt on each attempt so the body can be replayed
+		req, err := apiInfo.getHTTPRequest(ctx, method, url, body)
+		if err != nil {
+			return nil, apiInfo.redactor().redactURLError(err)
+		}
 
-	if acceptHeader != "" {
-		req.Header.Set("Accept", acceptHeader)
-	}
+		if acceptHeader != "" {
+			req.Header.Set("Accept", acceptHeader)
+		}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
+		cacheKey, cached := apiInfo.prepareConditional(req)
 
-	if resp.StatusCode >= 300 {
-		defer resp.Body.Close()
-		var requestBody, responseBody string
-		b, err := ioutil.ReadAll(resp.Body)
+		resp, err := apiInfo.httpClient().Do(req)
 		if err == nil {
-			responseBody = string(b)
+			apiInfo.recordRate(req, resp.Header)
+			if resp, err = apiInfo.applyCache(cacheKey, cached, resp); err == nil {
+				if err = apiInfo.checkResponse(resp, method, url, body); err == nil {
+					return resp, nil
+				}
+			}
 		}
-		if body != nil {
-			requestBody = *body
+
+		wait, retry := apiInfo.Retry.backoff(method, attempt, err)
+		if !retry {
+			return nil, apiInfo.redactor().redactURLError(err)
 		}
-		err = &ErrHTTPError{
-			Status:       resp.Status,
-			StatusCode:   resp.StatusCode,
-			Method:       method,
-			URL:          url,
-			RequestBody:  requestBody,
-			ResponseBody: responseBody,
+		if sleepErr := sleepContext(ctx, wait); sleepErr != nil {
+			return nil, apiInfo.redactor().redactURLError(err)
 		}
-		return nil, err
+	}
+}
+
+// checkResponse returns an error if the response status code is not 2xx. The response body is closed when an error
+// is returned. Secrets in the error are masked by the Redactor.
+func (apiInfo *APIInfo) checkResponse(resp *http.Response, method, url string, body *string) error {
+	if resp.StatusCode < 300 {
+		return nil
+	}
+
+	defer resp.Body.Close()
+	var requestBody, responseBody string
+	b, err := ioutil.ReadAll(resp.Body)
+	if err == nil {
+		responseBody = string(b)
+	}
+	if body != nil {
+		requestBody = *body
+	}
+	httpErr := &ErrHTTPError{
+		Status:        resp.Status,
+		StatusCode:    resp.StatusCode,
+		Method:        method,
+		URL:           url,
+		RequestBody:   requestBody,
+		ResponseBody:  responseBody,
+		ErrorResponse: parseErrorResponse(responseBody),
+	}
+	httpErr.RetryAfter, _ = parseRetryAfter(resp.Header)
+	err = rateLimitError(resp, httpErr)
+	apiInfo.redactor().redactHTTPError(httpErr)
+	return err
+}
+
+// httpClient returns the configured *http.Client with its transport wrapped by the Middleware chain.
+func (apiInfo *APIInfo) httpClient() *http.Client {
+	client := apiInfo.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if len(apiInfo.Middleware) == 0 {
+		return client
+	}
+
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for i := len(apiInfo.Middleware) - 1; i >= 0; i-- {
+		transport = apiInfo.Middleware[i](transport)
 	}
 
-	return resp, nil
+	wrapped := *client
+	wrapped.Transport = transport
+	return &wrapped
 }
 
-func (apiInfo *APIInfo) httpDelete(url string) (*http.Response, error) {
-	return apiInfo.doHTTPRequest("DELETE", url, nil, "")
+func (apiInfo *APIInfo) httpDelete(ctx context.Context, url string) (*http.Response, error) {
+	return apiInfo.doHTTPRequest(ctx, "DELETE", url, nil, "")
 }
 
-func (apiInfo *APIInfo) httpGet(url string) (*http.Response, error) {
-	return apiInfo.doHTTPRequest("GET", url, nil, "")
+func (apiInfo *APIInfo) httpGet(ctx context.Context, url string) (*http.Response, error) {
+	return apiInfo.doHTTPRequest(ctx, "GET", url, nil, "")
 }
 
-func (apiInfo *APIInfo) httpPatch(url, body string) (*http.Response, error) {
-	return apiInfo.doHTTPRequest("PATCH", url, &body, "")
+func (apiInfo *APIInfo) httpPatch(ctx context.Context, url, body string) (*http.Response, error) {
+	return apiInfo.doHTTPRequest(ctx, "PATCH", url, &body, "")
 }
 
-func (apiInfo *APIInfo) httpPut(url string, body string) (*http.Response, error) {
-	return apiInfo.doHTTPRequest("PUT", url, &body, "")
+func (apiInfo *APIInfo) httpPut(ctx context.Context, url string, body string) (*http.Response, error) {
+	return apiInfo.doHTTPRequest(ctx, "PUT", url, &body, "")
 }
 
-func (apiInfo *APIInfo) httpPost(url, body string) (*http.Response, error) {
-	return apiInfo.doHTTPRequest("POST", url, &body, "")
+func (apiInfo *APIInfo) httpPost(ctx context.Context, url, body string) (*http.Response, error) {
+	return apiInfo.doHTTPRequest(ctx, "POST", url, &body, "")
 }
@@ -1,7 +1,9 @@
 package ghapi
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -146,7 +148,7 @@ func TestApiInfo_addBaseUrl(t *testing.T) {
 
 func TestApiInfo_doHttpRequest_ReturnsErrOnParseError(t *testing.T) {
 	api := makeGitHubAPI()
-	resp, err := api.doHTTPRequest("GET", ":/noscheme", nil, "")
+	resp, err := api.doHTTPRequest(context.Background(), "GET", ":/noscheme", nil, "")
 	expectNil(t, resp, "resp")
 	expectNotNil(t, err, "err")
 	expect(t, "parse :/noscheme: missing protocol scheme", err.Error(), "err.Error()")
@@ -154,7 +156,7 @@ func TestApiInfo_doHttpRequest_ReturnsErrOnParseError(t *testing.T) {
 
 func TestAPIInfo_doHTTPRequest_ReturnsErrOnDoError(t *testing.T) {
 	api := makeGitHubAPI()
-	resp, err := api.doHTTPRequest("GET", "http://0.0.0.0:0/wat", nil, "")
+	resp, err := api.doHTTPRequest(context.Background(), "GET", "http://0.0.0.0:0/wat", nil, "")
 	expectNil(t, resp, "resp")
 	expectNotNil(t, err, "err")
 	expect(t, "Get http://0.0.0.0:0/wat: dial tcp 0.0.0.0:0: connectex: The requested address is not valid in its context.", err.Error(), "err.Error()")
@@ -172,7 +174,7 @@ func TestAPIInfo_doHTTPRequest_HasHeadersSet(t *testing.T) {
 	})
 	defer ts.Close()
 
-	resp, err := api.doHTTPRequest("GET", ts.URL+"/test_headers", nil, "")
+	resp, err := api.doHTTPRequest(context.Background(), "GET", ts.URL+"/test_headers", nil, "")
 	waitSignal(t, signal)
 
 	expectNotNil(t, resp, "resp")
@@ -193,7 +195,7 @@ func TestAPIInfo_doHTTPRequest_AuthorizationHeadersNotSetWhenAuthTokenIsEmpty(t
 	defer ts.Close()
 
 	api.OAuth2Token = ""
-	resp, err := api.doHTTPRequest("GET", ts.URL+"/test_headers", nil, "")
+	resp, err := api.doHTTPRequest(context.Background(), "GET", ts.URL+"/test_headers", nil, "")
 	waitSignal(t, signal)
 
 	expectNotNil(t, resp, "resp")
@@ -229,7 +231,7 @@ func TestRepositoryInfo_httpPatch(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	resp, err := api.httpPatch(ts.URL+"/test_patch", expectedBody)
+	resp, err := api.httpPatch(context.Background(), ts.URL+"/test_patch", expectedBody)
 	waitSignal(t, signal)
 
 	expectNotNil(t, resp, "resp")
@@ -255,7 +257,7 @@ func TestRepositoryInfo_httpPatch_ErrorPopulated(t *testing.T) {
 	defer ts.Close()
 
 	expectedURL := ts.URL + "/test_patch"
-	resp, err := api.httpPatch(expectedURL, expectedRequestBody)
+	resp, err := api.httpPatch(context.Background(), expectedURL, expectedRequestBody)
 	waitSignal(t, signal)
 
 	expectNil(t, resp, "resp")
@@ -273,3 +275,44 @@ func TestRepositoryInfo_httpPatch_ErrorPopulated(t *testing.T) {
 	expect(t, expectedResponseBody, e.ResponseBody, "e.ResponseBody")
 	expect(t, expectedURL, e.URL, "e.Url")
 }
+
+func TestAPIInfo_doHTTPRequest_ContextCanceled(t *testing.T) {
+	ts, api, _ := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("request should not be sent")
+	})
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	issue, err := api.Issue.GetIssueContext(ctx, 1347)
+
+	expectNil(t, issue, "issue")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRepositoryAPI_ForkContext_StopsWhenContextDone(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && r.URL.Path == "/repos/test_owner/test_repository/forks" {
+			w.WriteHeader(202)
+			if _, err := w.Write([]byte(`{"id":1}`)); err != nil {
+				t.Fatal(err)
+			}
+			return
+		}
+		w.WriteHeader(409)
+	}))
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	forkResponse, err := api.Repository.ForkContext(ctx, time.Minute)
+
+	expectNotNil(t, forkResponse, "forkResponse")
+	expect(t, context.DeadlineExceeded, err, "err")
+}
@@ -0,0 +1,260 @@
+package ghapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// appJWTLifetime is how long a GitHub App JWT is valid for. GitHub allows at most 10 minutes.
+	appJWTLifetime = 9 * time.Minute
+	// appJWTClockDrift is subtracted from "iat" to allow for clock drift between us and GitHub.
+	appJWTClockDrift = time.Minute
+	// defaultTokenRefreshBefore is how long before expiry a cached token is refreshed.
+	defaultTokenRefreshBefore = 5 * time.Minute
+
+	machineManPreviewAcceptHeader = "application/vnd.github.machine-man-preview+json"
+)
+
+// Token is a credential returned by a TokenSource.
+type Token struct {
+	// Value is the token sent in the "Authorization" header.
+	Value string
+	// Type is the "Authorization" header scheme, either "token" or "Bearer". If empty, "token" is used.
+	Type string
+	// ExpiresAt is when the token expires. The zero value means the token doesn't expire.
+	ExpiresAt time.Time
+}
+
+// TokenSource supplies the token used to authenticate each request. Implementations must be safe for concurrent use.
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// WithTokenSource authenticates requests with tokens from ts instead of the static OAuth2Token.
+func WithTokenSource(ts TokenSource) Option {
+	return func(apiInfo *APIInfo) {
+		apiInfo.TokenSource = ts
+	}
+}
+
+// StaticTokenSource returns a TokenSource which always returns token, sent as "Authorization: token <token>".
+func StaticTokenSource(token string) TokenSource {
+	return staticTokenSource{token: &Token{Value: token}}
+}
+
+type staticTokenSource struct {
+	token *Token
+}
+
+func (s staticTokenSource) Token(ctx context.Context) (*Token, error) {
+	return s.token, nil
+}
+
+// AppTokenSource is a TokenSource which authenticates as a GitHub App by signing RS256 JWTs with the App's private
+// key. JWTs can only call the /app endpoints; use InstallationTokenSource to act on repositories.
+// See https://developer.github.com/apps/building-github-apps/authenticating-with-github-apps/#authenticating-as-a-github-app.
+type AppTokenSource struct {
+	appID int64
+	key   *rsa.PrivateKey
+	now   func() time.Time
+
+	mtx   sync.Mutex
+	token *Token
+}
+
+// NewAppTokenSource returns an AppTokenSource for the App with the specified ID. privateKeyPEM is the contents of
+// the private key file downloaded from the App's settings page, in PKCS#1 or PKCS#8 format.
+func NewAppTokenSource(appID int64, privateKeyPEM []byte) (*AppTokenSource, error) {
+	key, err := parseRSAPrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &AppTokenSource{appID: appID, key: key, now: time.Now}, nil
+}
+
+// Token returns a signed JWT. A JWT is reused until it's within a minute of expiring.
+func (s *AppTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := s.now()
+	if s.token != nil && now.Add(time.Minute).Before(s.token.ExpiresAt) {
+		return s.token, nil
+	}
+
+	expiresAt := now.Add(appJWTLifetime)
+	jwt, err := signJWT(s.key, map[string]interface{}{
+		"iat": now.Add(-appJWTClockDrift).Unix(),
+		"exp": expiresAt.Unix(),
+		"iss": s.appID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.token = &Token{Value: jwt, Type: "Bearer", ExpiresAt: expiresAt}
+	return s.token, nil
+}
+
+// InstallationToken is an installation access token returned by /app/installations/:installation_id/access_tokens.
+type InstallationToken struct {
+	Token        string            `json:"token"`
+	ExpiresAt    time.Time         `json:"expires_at"`
+	Permissions  map[string]string `json:"permissions"`
+	Repositories []struct {
+		ID       int64  `json:"id"`
+		Name     string `json:"name"`
+		FullName string `json:"full_name"`
+	} `json:"repositories"`
+}
+
+// InstallationTokenOptions restricts the repositories and permissions of an installation access token. The zero
+// value grants everything the installation has access to.
+type InstallationTokenOptions struct {
+	RepositoryIDs []int64           `json:"repository_ids,omitempty"`
+	Permissions   map[string]string `json:"permissions,omitempty"`
+}
+
+// InstallationTokenSource is a TokenSource which exchanges an App's JWT for installation access tokens. Tokens are
+// cached and refreshed RefreshBefore their expiry, so a GitHubAPI created with WithTokenSource can be used
+// indefinitely.
+// See https://developer.github.com/apps/building-github-apps/authenticating-with-github-apps/#authenticating-as-an-installation.
+type InstallationTokenSource struct {
+	// Options restricts the tokens created. It must not be changed after the first call to Token.
+	Options InstallationTokenOptions
+	// RefreshBefore is how long before expiry a token is refreshed. Defaults to 5 minutes.
+	RefreshBefore time.Duration
+
+	apiInfo        APIInfo
+	installationID int64
+	now            func() time.Time
+
+	mtx   sync.Mutex
+	token *Token
+}
+
+// NewInstallationTokenSource returns an InstallationTokenSource for the specified installation. Tokens are requested
+// from baseURL, authenticated with app; opts configure the requests (for example, WithHTTPClient). An Authenticator
+// set by opts is ignored, since the token exchange must be authenticated with app's JWT.
+func NewInstallationTokenSource(baseURL string, installationID int64, app *AppTokenSource, opts ...Option) *InstallationTokenSource {
+	apiInfo := newAPIInfo(baseURL, "", opts)
+	apiInfo.TokenSource = app
+	// an Authenticator takes precedence over TokenSource
+	apiInfo.Authenticator = nil
+	// access tokens must never be served from a cache
+	apiInfo.Cache = nil
+
+	return &InstallationTokenSource{
+		apiInfo:        apiInfo,
+		installationID: installationID,
+		now:            time.Now,
+	}
+}
+
+// Token returns a cached installation access token, creating a new one if there isn't one or it's about to expire.
+func (s *InstallationTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	refreshBefore := s.RefreshBefore
+	if refreshBefore <= 0 {
+		refreshBefore = defaultTokenRefreshBefore
+	}
+
+	if s.token != nil && s.now().Add(refreshBefore).Before(s.token.ExpiresAt) {
+		return s.token, nil
+	}
+
+	installationToken, err := s.CreateToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.token = &Token{Value: installationToken.Token, Type: "token", ExpiresAt: installationToken.ExpiresAt}
+	return s.token, nil
+}
+
+// CreateToken creates a new installation access token, bypassing the cache.
+func (s *InstallationTokenSource) CreateToken(ctx context.Context) (*InstallationToken, error) {
+	url := s.apiInfo.addBaseURL(fmt.Sprintf("/app/installations/%d/access_tokens", s.installationID))
+
+	var body *string
+	if len(s.Options.RepositoryIDs) != 0 || len(s.Options.Permissions) != 0 {
+		b, err := json.Marshal(s.Options)
+		if err != nil {
+			return nil, err
+		}
+		str := string(b)
+		body = &str
+	}
+
+	resp, err := s.apiInfo.doHTTPRequest(ctx, "POST", url, body, machineManPreviewAcceptHeader)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var token InstallationToken
+
+	j := json.NewDecoder(resp.Body)
+	if err = j.Decode(&token); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// signJWT returns an RS256 signed JWT containing claims.
+func signJWT(key *rsa.PrivateKey, claims map[string]interface{}) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString(header) + "." + enc.EncodeToString(payload)
+
+	hash := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + enc.EncodeToString(sig), nil
+}
+
+func parseRSAPrivateKey(privateKeyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, ErrInvalidPrivateKey
+	}
+	return key, nil
+}
@@ -0,0 +1,222 @@
+package ghapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const expectedAppID = 1234
+
+func makeTestPrivateKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return key, privateKeyPEM
+}
+
+// verifyTestJWT checks the JWT's signature and returns its claims.
+func verifyTestJWT(t *testing.T, key *rsa.PrivateKey, jwt string) map[string]interface{} {
+	parts := strings.Split(jwt, ".")
+	expect(t, 3, len(parts), "len(parts)")
+
+	enc := base64.RawURLEncoding
+	sig, err := enc.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err = rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hash[:], sig); err != nil {
+		t.Fatalf("signature: %v", err)
+	}
+
+	header, err := enc.DecodeString(parts[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, `{"alg":"RS256","typ":"JWT"}`, string(header), "header")
+
+	payload, err := enc.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var claims map[string]interface{}
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		t.Fatal(err)
+	}
+	return claims
+}
+
+func TestAppTokenSource_Token(t *testing.T) {
+	key, privateKeyPEM := makeTestPrivateKey(t)
+
+	app, err := NewAppTokenSource(expectedAppID, privateKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1500000000, 0)
+	app.now = func() time.Time { return now }
+
+	token, err := app.Token(context.Background())
+	expectNil(t, err, "err")
+	expect(t, "Bearer", token.Type, "token.Type")
+	expect(t, now.Add(9*time.Minute), token.ExpiresAt, "token.ExpiresAt")
+
+	claims := verifyTestJWT(t, key, token.Value)
+	expect(t, float64(expectedAppID), claims["iss"], "claims[\"iss\"]")
+	expect(t, float64(now.Add(-time.Minute).Unix()), claims["iat"], "claims[\"iat\"]")
+	expect(t, float64(now.Add(9*time.Minute).Unix()), claims["exp"], "claims[\"exp\"]")
+
+	// reused until it's about to expire
+	now = now.Add(7 * time.Minute)
+	cached, err := app.Token(context.Background())
+	expectNil(t, err, "err")
+	expect(t, token.Value, cached.Value, "cached.Value")
+
+	now = now.Add(time.Minute)
+	refreshed, err := app.Token(context.Background())
+	expectNil(t, err, "err")
+	if refreshed.Value == token.Value {
+		t.Fatal("expected a new JWT")
+	}
+}
+
+func TestNewAppTokenSource_PKCS8(t *testing.T) {
+	key, _ := makeTestPrivateKey(t)
+	b, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewAppTokenSource(expectedAppID, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: b}))
+	expectNil(t, err, "err")
+}
+
+func TestNewAppTokenSource_InvalidKey(t *testing.T) {
+	_, err := NewAppTokenSource(expectedAppID, []byte("not a key"))
+	expect(t, ErrInvalidPrivateKey, err, "err")
+
+	_, err = NewAppTokenSource(expectedAppID, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte("garbage")}))
+	expect(t, ErrInvalidPrivateKey, err, "err")
+}
+
+func TestInstallationTokenSource(t *testing.T) {
+	key, privateKeyPEM := makeTestPrivateKey(t)
+
+	var exchanges int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/app/installations/42/access_tokens" {
+			n := atomic.AddInt32(&exchanges, 1)
+
+			expect(t, "POST", r.Method, "r.Method")
+			expect(t, machineManPreviewAcceptHeader, r.Header.Get("Accept"), "Accept")
+			auth := r.Header.Get("Authorization")
+			if !strings.HasPrefix(auth, "Bearer ") {
+				t.Fatalf("Authorization '%s' is not a Bearer token", auth)
+			}
+			verifyTestJWT(t, key, strings.TrimPrefix(auth, "Bearer "))
+
+			w.WriteHeader(201)
+			_, err := fmt.Fprintf(w, `{"token":"v1.token%d","expires_at":"2017-07-14T03:40:00Z"}`, n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return
+		}
+
+		expect(t, "/repos/test_owner/test_repository", r.URL.Path, "r.URL.Path")
+		expect(t, fmt.Sprintf("token v1.token%d", atomic.LoadInt32(&exchanges)), r.Header.Get("Authorization"), "Authorization")
+		if _, err := w.Write([]byte(`{"name":"test_repository"}`)); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer ts.Close()
+
+	app, err := NewAppTokenSource(expectedAppID, privateKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installation := NewInstallationTokenSource(ts.URL, 42, app)
+	now := time.Date(2017, 7, 14, 2, 40, 0, 0, time.UTC)
+	installation.now = func() time.Time { return now }
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, "", WithTokenSource(installation))
+
+	for i := 0; i < 2; i++ {
+		repo, err := api.Repository.Get()
+		expectNil(t, err, "err")
+		expect(t, "test_repository", repo.Name, "repo.Name")
+	}
+	expect(t, int32(1), atomic.LoadInt32(&exchanges), "exchanges")
+
+	// within RefreshBefore of expiry
+	now = now.Add(56 * time.Minute)
+	_, err = api.Repository.Get()
+	expectNil(t, err, "err")
+	expect(t, int32(2), atomic.LoadInt32(&exchanges), "exchanges")
+}
+
+func TestInstallationTokenSource_IgnoresAuthenticator(t *testing.T) {
+	key, privateKeyPEM := makeTestPrivateKey(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "Bearer ") {
+			t.Fatalf("Authorization '%s' is not a Bearer token", auth)
+		}
+		verifyTestJWT(t, key, strings.TrimPrefix(auth, "Bearer "))
+
+		w.WriteHeader(201)
+		if _, err := w.Write([]byte(`{"token":"v1.token1","expires_at":"2017-07-14T03:40:00Z"}`)); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer ts.Close()
+
+	app, err := NewAppTokenSource(expectedAppID, privateKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installation := NewInstallationTokenSource(ts.URL, 42, app, WithAuthenticator(TokenAuth("personal-token")))
+	token, err := installation.CreateToken(context.Background())
+
+	expectNil(t, err, "err")
+	expect(t, "v1.token1", token.Token, "token.Token")
+}
+
+func TestInstallationTokenSource_ReturnsErrHTTPError(t *testing.T) {
+	_, privateKeyPEM := makeTestPrivateKey(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(401)
+	}))
+	defer ts.Close()
+
+	app, err := NewAppTokenSource(expectedAppID, privateKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, "",
+		WithTokenSource(NewInstallationTokenSource(ts.URL, 42, app)))
+	_, err = api.Repository.Get()
+
+	expect(t, true, IsHTTPError(err, 401), "IsHTTPError(err, 401)")
+}
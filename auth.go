@@ -0,0 +1,195 @@
+package ghapi
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Authenticator adds credentials to each request. Implementations must be safe for concurrent use.
+// See https://developer.github.com/v3/#authentication.
+type Authenticator interface {
+	// Authenticate adds credentials to req. It's called once per attempt, so the credentials may change between
+	// retries. req.Context() is the context of the API call.
+	Authenticate(req *http.Request) error
+}
+
+// RateObserver is implemented by Authenticators which need the rate limit reported by each response, such as
+// TokenPool.
+type RateObserver interface {
+	// ObserveRate is called with the rate limit reported in the response to req, which was authenticated by the
+	// Authenticator.
+	ObserveRate(req *http.Request, resource string, rate Rate)
+}
+
+// WithAuthenticator authenticates requests with auth. It takes precedence over TokenSource and OAuth2Token.
+func WithAuthenticator(auth Authenticator) Option {
+	return func(apiInfo *APIInfo) {
+		apiInfo.Authenticator = auth
+	}
+}
+
+// TokenAuth authenticates with a personal access or OAuth token, sent as "Authorization: token <token>". This is
+// what NewGitHubAPI uses for authToken.
+type TokenAuth string
+
+// Authenticate adds the "Authorization" header to req.
+func (a TokenAuth) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", string(a)))
+	return nil
+}
+
+// BearerAuth authenticates with a token sent as "Authorization: Bearer <token>", such as a GitHub App JWT.
+type BearerAuth string
+
+// Authenticate adds the "Authorization" header to req.
+func (a BearerAuth) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", string(a)))
+	return nil
+}
+
+// BasicAuth authenticates with a username and password. If the account has two-factor authentication enabled OTP
+// must be set to supply the current one-time password, which is sent in the "X-GitHub-OTP" header.
+// See https://developer.github.com/v3/auth/#working-with-two-factor-authentication.
+type BasicAuth struct {
+	Username string
+	Password string
+	// OTP returns the current one-time password. If nil, or if it returns an empty string, no "X-GitHub-OTP" header
+	// is sent.
+	OTP func() (string, error)
+}
+
+// Authenticate adds the "Authorization" and "X-GitHub-OTP" headers to req.
+func (a BasicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.Username, a.Password)
+	if a.OTP == nil {
+		return nil
+	}
+
+	otp, err := a.OTP()
+	if err != nil {
+		return err
+	}
+	if otp != "" {
+		req.Header.Set("X-GitHub-OTP", otp)
+	}
+	return nil
+}
+
+// TokenSourceAuth authenticates with tokens from a TokenSource, such as an InstallationTokenSource.
+type TokenSourceAuth struct {
+	TokenSource TokenSource
+}
+
+// Authenticate adds the "Authorization" header to req, using the token type returned by the TokenSource.
+func (a TokenSourceAuth) Authenticate(req *http.Request) error {
+	token, err := a.TokenSource.Token(req.Context())
+	if err != nil {
+		return err
+	}
+
+	tokenType := token.Type
+	if tokenType == "" {
+		tokenType = "token"
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("%s %s", tokenType, token.Value))
+	return nil
+}
+
+// TokenPool spreads requests across several tokens. Each request uses the token with the most requests remaining
+// for the request's rate limit resource, as reported by the previous responses authenticated with that token.
+// Tokens which haven't been used yet, or whose rate limit has reset, are assumed to have their full limit
+// available. Ties are broken round-robin.
+type TokenPool struct {
+	mtx    sync.Mutex
+	tokens []string
+	index  map[string]int
+	rates  []map[string]Rate
+	next   int
+	now    func() time.Time
+}
+
+// NewTokenPool returns a TokenPool for the specified tokens, each sent as "Authorization: token <token>".
+func NewTokenPool(tokens ...string) *TokenPool {
+	p := &TokenPool{
+		tokens: tokens,
+		index:  make(map[string]int, len(tokens)),
+		rates:  make([]map[string]Rate, len(tokens)),
+		now:    time.Now,
+	}
+	for i, token := range tokens {
+		p.index[token] = i
+		p.rates[i] = make(map[string]Rate)
+	}
+	return p
+}
+
+// Authenticate adds the "Authorization" header for the token with the most requests remaining.
+func (p *TokenPool) Authenticate(req *http.Request) error {
+	if len(p.tokens) == 0 {
+		return ErrTokenPoolEmpty
+	}
+
+	resource := requestRateResource(req)
+	now := p.now()
+
+	p.mtx.Lock()
+	best, bestRemaining := -1, -1
+	for i := 0; i < len(p.tokens); i++ {
+		n := (p.next + i) % len(p.tokens)
+		remaining := math.MaxInt32 // unknown, assume the full limit
+		if rate, ok := p.rates[n][resource]; ok && now.Before(rate.Reset) {
+			remaining = rate.Remaining
+		}
+		if remaining > bestRemaining {
+			best, bestRemaining = n, remaining
+		}
+	}
+	p.next = (best + 1) % len(p.tokens)
+	token := p.tokens[best]
+	p.mtx.Unlock()
+
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+	return nil
+}
+
+// ObserveRate records the rate limit remaining for the token used by req.
+func (p *TokenPool) ObserveRate(req *http.Request, resource string, rate Rate) {
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "token ")
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if i, ok := p.index[token]; ok {
+		p.rates[i][resource] = rate
+	}
+}
+
+// requestRateResource returns the rate limit resource a request counts against.
+func requestRateResource(req *http.Request) string {
+	path := req.URL.Path
+	switch {
+	case strings.HasPrefix(path, "/search/") || strings.HasPrefix(path, "/api/v3/search/"):
+		return SearchRateLimitResource
+	case path == "/graphql" || path == "/api/graphql":
+		return GraphQLRateLimitResource
+	}
+	return CoreRateLimitResource
+}
+
+// authenticator returns the Authenticator for requests: Authenticator if set, otherwise TokenSource, otherwise
+// OAuth2Token. It returns nil for unauthenticated requests.
+func (apiInfo *APIInfo) authenticator() Authenticator {
+	switch {
+	case apiInfo.Authenticator != nil:
+		return apiInfo.Authenticator
+	case apiInfo.TokenSource != nil:
+		return TokenSourceAuth{TokenSource: apiInfo.TokenSource}
+	case apiInfo.OAuth2Token != "":
+		return TokenAuth(apiInfo.OAuth2Token)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package ghapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testAuthenticator(t *testing.T, auth Authenticator, check func(r *http.Request)) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		check(r)
+		if _, err := w.Write([]byte(`{"name":"master"}`)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	defer ts.Close()
+
+	api.Branch.Authenticator = auth
+	_, err := api.Branch.GetBranch("master")
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+}
+
+func TestTokenAuth(t *testing.T) {
+	testAuthenticator(t, TokenAuth("abc"), func(r *http.Request) {
+		expect(t, "token abc", r.Header.Get("Authorization"), "Authorization")
+	})
+}
+
+func TestBearerAuth(t *testing.T) {
+	testAuthenticator(t, BearerAuth("abc"), func(r *http.Request) {
+		expect(t, "Bearer abc", r.Header.Get("Authorization"), "Authorization")
+	})
+}
+
+func TestBasicAuth(t *testing.T) {
+	testAuthenticator(t, BasicAuth{Username: "octocat", Password: "hunter2"}, func(r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		expect(t, true, ok, "ok")
+		expect(t, "octocat", username, "username")
+		expect(t, "hunter2", password, "password")
+		expect(t, "", r.Header.Get("X-GitHub-OTP"), "X-GitHub-OTP")
+	})
+}
+
+func TestBasicAuth_OTP(t *testing.T) {
+	auth := BasicAuth{
+		Username: "octocat",
+		Password: "hunter2",
+		OTP:      func() (string, error) { return "123456", nil },
+	}
+	testAuthenticator(t, auth, func(r *http.Request) {
+		_, _, ok := r.BasicAuth()
+		expect(t, true, ok, "ok")
+		expect(t, "123456", r.Header.Get("X-GitHub-OTP"), "X-GitHub-OTP")
+	})
+}
+
+func TestBasicAuth_OTPError(t *testing.T) {
+	otpErr := errors.New("no device")
+	api := NewGitHubAPI("http://127.0.0.1:0", expectedOwner, expectedRepository, "",
+		WithAuthenticator(BasicAuth{OTP: func() (string, error) { return "", otpErr }}))
+
+	_, err := api.Branch.GetBranch("master")
+
+	expect(t, otpErr, err, "err")
+}
+
+func TestAuthenticator_TakesPrecedenceOverOAuth2Token(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "Bearer abc", r.Header.Get("Authorization"), "Authorization")
+		if _, err := w.Write([]byte(`{}`)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	defer ts.Close()
+
+	api.User.Authenticator = BearerAuth("abc")
+	_, err := api.User.GetUser("octocat")
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+}
+
+func TestTokenPool_UsesTokenWithMostRemaining(t *testing.T) {
+	remaining := map[string]int{"token a": 10, "token b": 4000, "token c": 50}
+
+	var mtx sync.Mutex
+	var used []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+
+		mtx.Lock()
+		used = append(used, auth)
+		remaining[auth]--
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining[auth]))
+		w.Header().Set("X-RateLimit-Reset", "4102444800") // 2100-01-01
+		mtx.Unlock()
+
+		if _, err := w.Write([]byte(`{"name":"master"}`)); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, "", WithAuthenticator(NewTokenPool("a", "b", "c")))
+	for i := 0; i < 6; i++ {
+		_, err := api.Branch.GetBranch("master")
+		expectNil(t, err, "err")
+	}
+
+	// each token is tried once since its rate limit is unknown, then the one with the most remaining is used
+	expected := []string{"token a", "token b", "token c", "token b", "token b", "token b"}
+	expect(t, len(expected), len(used), "len(used)")
+	for i := range expected {
+		expect(t, expected[i], used[i], "used["+strconv.Itoa(i)+"]")
+	}
+}
+
+func TestTokenPool_ReusesTokenAfterReset(t *testing.T) {
+	pool := NewTokenPool("a", "b")
+	now := time.Unix(1500000000, 0)
+	pool.now = func() time.Time { return now }
+
+	req, err := http.NewRequest("GET", "https://api.github.com/repos/test_owner/test_repository", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Authorization", "token a")
+	pool.ObserveRate(req, CoreRateLimitResource, Rate{Limit: 5000, Remaining: 0, Reset: now.Add(time.Minute)})
+	req.Header.Set("Authorization", "token b")
+	pool.ObserveRate(req, CoreRateLimitResource, Rate{Limit: 5000, Remaining: 1, Reset: now.Add(time.Minute)})
+
+	expectNil(t, pool.Authenticate(req), "pool.Authenticate(req)")
+	expect(t, "token b", req.Header.Get("Authorization"), "Authorization")
+
+	// the search limit is tracked separately
+	searchReq, err := http.NewRequest("GET", "https://api.github.com/search/issues?q=test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectNil(t, pool.Authenticate(searchReq), "pool.Authenticate(searchReq)")
+	expect(t, "token a", searchReq.Header.Get("Authorization"), "Authorization")
+
+	// once the limits reset both tokens are used again
+	now = now.Add(2 * time.Minute)
+	used := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		expectNil(t, pool.Authenticate(req), "pool.Authenticate(req)")
+		used[req.Header.Get("Authorization")] = true
+	}
+	expect(t, true, used["token a"], "used[\"token a\"]")
+	expect(t, true, used["token b"], "used[\"token b\"]")
+}
+
+func TestTokenPool_Empty(t *testing.T) {
+	api := NewGitHubAPI("http://127.0.0.1:0", expectedOwner, expectedRepository, "", WithAuthenticator(NewTokenPool()))
+
+	_, err := api.Branch.GetBranch("master")
+
+	expect(t, ErrTokenPoolEmpty, err, "err")
+}
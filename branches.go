@@ -1,6 +1,7 @@
 package ghapi
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"io/ioutil"
@@ -60,9 +61,14 @@ type Branch struct {
 
 // GetBranch gets a branch by name.
 func (api *BranchesAPI) GetBranch(branch string) (*Branch, error) {
+	return api.GetBranchContext(context.Background(), branch)
+}
+
+// GetBranchContext is like GetBranch but uses the provided context.
+func (api *BranchesAPI) GetBranchContext(ctx context.Context, branch string) (*Branch, error) {
 	url := api.getURL("/repos/:owner/:repo/branches/" + url.PathEscape(branch))
 
-	resp, err := api.httpGet(url)
+	resp, err := api.httpGet(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -121,6 +127,11 @@ type Restrictions struct {
 
 // Protect enables branch protection for the specified branch.
 func (api *BranchesAPI) Protect(branch string, opts BranchProtection) error {
+	return api.ProtectContext(context.Background(), branch, opts)
+}
+
+// ProtectContext is like Protect but uses the provided context.
+func (api *BranchesAPI) ProtectContext(ctx context.Context, branch string, opts BranchProtection) error {
 	b, err := json.Marshal(opts)
 	if err != nil {
 		return err
@@ -129,7 +140,7 @@ func (api *BranchesAPI) Protect(branch string, opts BranchProtection) error {
 	apiURL := api.getURL("/repos/:owner/:repo/branches/" + url.PathEscape(branch) + "/protection")
 
 	body := string(b)
-	resp, err := api.doHTTPRequest("PUT", apiURL, &body, "application/vnd.github.loki-preview+json")
+	resp, err := api.doHTTPRequest(ctx, "PUT", apiURL, &body, "application/vnd.github.loki-preview+json")
 	if err != nil {
 		return err
 	}
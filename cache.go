@@ -0,0 +1,311 @@
+package ghapi
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+)
+
+// Cache stores GET responses so they can be revalidated with conditional requests. GitHub doesn't count a
+// "304 Not Modified" response against the rate limit.
+// See https://developer.github.com/v3/#conditional-requests.
+//
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the cached response for key, if any.
+	Get(key string) (*CachedResponse, bool)
+	// Set stores the response for key.
+	Set(key string, resp *CachedResponse)
+	// Delete removes the cached response for key.
+	Delete(key string)
+}
+
+// CachedResponse is a response stored in a Cache.
+type CachedResponse struct {
+	ETag         string      `json:"etag"`
+	LastModified string      `json:"last_modified"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+// CacheStats contains cache statistics for an APIInfo. See APIInfo.CacheStats.
+type CacheStats struct {
+	// Hits is the number of requests answered with "304 Not Modified" and served from the cache.
+	Hits int64
+	// Misses is the number of cacheable requests which returned a new body.
+	Misses int64
+}
+
+type cacheStats struct {
+	hits   int64
+	misses int64
+}
+
+// WithCache enables conditional requests for GET calls, using cache to store responses.
+func WithCache(cache Cache) Option {
+	return func(apiInfo *APIInfo) {
+		apiInfo.Cache = cache
+	}
+}
+
+// CacheStats returns the cache hit and miss counts. The counts are shared by all API structs created by
+// NewGitHubAPI.
+func (apiInfo *APIInfo) CacheStats() CacheStats {
+	if apiInfo.cacheStats == nil {
+		return CacheStats{}
+	}
+	return CacheStats{
+		Hits:   atomic.LoadInt64(&apiInfo.cacheStats.hits),
+		Misses: atomic.LoadInt64(&apiInfo.cacheStats.misses),
+	}
+}
+
+// cacheKey returns the key for req. The Accept and Authorization headers are part of the key since they can change
+// the response; the Authorization header is hashed so credentials aren't stored in the key.
+func cacheKey(req *http.Request) string {
+	auth := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return req.Header.Get("Accept") + " " + hex.EncodeToString(auth[:8]) + " " + req.URL.String()
+}
+
+// prepareConditional adds the "If-None-Match" and "If-Modified-Since" headers to req if there's a cached response.
+// It returns the cache key and cached response, or an empty key if req isn't cacheable.
+func (apiInfo *APIInfo) prepareConditional(req *http.Request) (string, *CachedResponse) {
+	if apiInfo.Cache == nil || req.Method != "GET" {
+		return "", nil
+	}
+
+	key := cacheKey(req)
+	cached, ok := apiInfo.Cache.Get(key)
+	if !ok {
+		return key, nil
+	}
+
+	if cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	if cached.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.LastModified)
+	}
+
+	return key, cached
+}
+
+// applyCache returns the cached body for a "304 Not Modified" response, and stores successful responses which have
+// an ETag or Last-Modified header.
+func (apiInfo *APIInfo) applyCache(key string, cached *CachedResponse, resp *http.Response) (*http.Response, error) {
+	if key == "" {
+		return resp, nil
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		resp.Body.Close()
+		apiInfo.countCache(true)
+
+		// keep the fresh headers (ie: rate limits) but fill in anything the 304 left out
+		header := resp.Header
+		for k, v := range cached.Header {
+			if _, ok := header[k]; !ok {
+				header[k] = v
+			}
+		}
+
+		resp.StatusCode = http.StatusOK
+		resp.Status = "200 OK"
+		resp.Body = ioutil.NopCloser(bytes.NewReader(cached.Body))
+		resp.ContentLength = int64(len(cached.Body))
+		return resp, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	apiInfo.countCache(false)
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return resp, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	apiInfo.Cache.Set(key, &CachedResponse{
+		ETag:         etag,
+		LastModified: lastModified,
+		Header:       resp.Header,
+		Body:         body,
+	})
+
+	return resp, nil
+}
+
+func (apiInfo *APIInfo) countCache(hit bool) {
+	if apiInfo.cacheStats == nil {
+		return
+	}
+	if hit {
+		atomic.AddInt64(&apiInfo.cacheStats.hits, 1)
+	} else {
+		atomic.AddInt64(&apiInfo.cacheStats.misses, 1)
+	}
+}
+
+// MemoryCache is an in-memory Cache which evicts the least recently used response once it holds MaxEntries.
+type MemoryCache struct {
+	maxEntries int
+
+	mtx     sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key  string
+	resp *CachedResponse
+}
+
+// NewMemoryCache returns a MemoryCache holding up to maxEntries responses. If maxEntries is zero or less the cache
+// is unbounded.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		lru:        list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached response for key, if any.
+func (c *MemoryCache) Get(key string) (*CachedResponse, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*memoryCacheEntry).resp, true
+}
+
+// Set stores the response for key.
+func (c *MemoryCache) Set(key string, resp *CachedResponse) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*memoryCacheEntry).resp = resp
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&memoryCacheEntry{key: key, resp: resp})
+
+	if c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// Delete removes the cached response for key.
+func (c *MemoryCache) Delete(key string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+	}
+}
+
+// Len returns the number of cached responses.
+func (c *MemoryCache) Len() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.lru.Len()
+}
+
+// DiskCache is a Cache which stores each response as a JSON file in a directory.
+type DiskCache struct {
+	dir string
+	mtx sync.Mutex
+}
+
+// NewDiskCache returns a DiskCache storing responses in dir. The directory is created if it doesn't exist.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// Get returns the cached response for key, if any. Unreadable entries are treated as missing.
+func (c *DiskCache) Get(key string) (*CachedResponse, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	b, err := ioutil.ReadFile(c.filename(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var resp CachedResponse
+	if err = json.Unmarshal(b, &resp); err != nil {
+		return nil, false
+	}
+	return &resp, true
+}
+
+// Set stores the response for key. Errors writing to disk are ignored; the response is simply not cached.
+func (c *DiskCache) Set(key string, resp *CachedResponse) {
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	// write to a temp file and rename so a reader never sees a partial entry
+	tmp, err := ioutil.TempFile(c.dir, "tmp-")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(b)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err = os.Rename(tmp.Name(), c.filename(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// Delete removes the cached response for key.
+func (c *DiskCache) Delete(key string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	os.Remove(c.filename(key))
+}
+
+func (c *DiskCache) filename(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,152 @@
+package ghapi
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+const getBranchResponse = `{"name":"master","commit":{"sha":"6dcb09b5b57875f334f61aebed695e2e4193db5e"}}`
+
+func makeETagTestServer(t *testing.T) (*httptest.Server, *int32) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		if r.Header.Get("If-None-Match") == `"abc"` {
+			w.WriteHeader(304)
+			return
+		}
+		w.Header().Set("ETag", `"abc"`)
+		if _, err := w.Write([]byte(getBranchResponse)); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	return ts, &requests
+}
+
+func TestCache_RevalidatesWithETag(t *testing.T) {
+	ts, requests := makeETagTestServer(t)
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken, WithCache(NewMemoryCache(10)))
+
+	for i := 0; i < 3; i++ {
+		branch, err := api.Branch.GetBranch("master")
+		expectNil(t, err, "err")
+		expect(t, "master", branch.Name, "branch.Name")
+		expect(t, "6dcb09b5b57875f334f61aebed695e2e4193db5e", branch.Commit.SHA, "branch.Commit.SHA")
+	}
+
+	expect(t, int32(3), atomic.LoadInt32(requests), "requests")
+	stats := api.Repository.CacheStats()
+	expect(t, int64(2), stats.Hits, "stats.Hits")
+	expect(t, int64(1), stats.Misses, "stats.Misses")
+}
+
+func TestCache_DoesNotCacheWrites(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "", r.Header.Get("If-None-Match"), "If-None-Match")
+		w.Header().Set("ETag", `"abc"`)
+		w.WriteHeader(201)
+	})
+	defer ts.Close()
+
+	cache := NewMemoryCache(10)
+	api.Cache = cache
+	resp, err := api.httpPost(context.Background(), ts.URL+"/test", "{}")
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	defer resp.Body.Close()
+	expect(t, 0, cache.Len(), "cache.Len()")
+}
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewMemoryCache(2)
+
+	cache.Set("a", &CachedResponse{ETag: "a"})
+	cache.Set("b", &CachedResponse{ETag: "b"})
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("expected a to be cached")
+	}
+	cache.Set("c", &CachedResponse{ETag: "c"})
+
+	_, ok := cache.Get("b")
+	expect(t, false, ok, "b cached")
+	_, ok = cache.Get("a")
+	expect(t, true, ok, "a cached")
+	_, ok = cache.Get("c")
+	expect(t, true, ok, "c cached")
+	expect(t, 2, cache.Len(), "cache.Len()")
+
+	cache.Delete("a")
+	_, ok = cache.Get("a")
+	expect(t, false, ok, "a cached after Delete")
+}
+
+func TestDiskCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ghapi-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cache, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, ok := cache.Get("key")
+	expect(t, false, ok, "ok")
+
+	cache.Set("key", &CachedResponse{
+		ETag:   `"abc"`,
+		Header: http.Header{"Link": []string{"<http://example.org?page=2>; rel=\"next\""}},
+		Body:   []byte(getBranchResponse),
+	})
+
+	cached, ok := cache.Get("key")
+	expect(t, true, ok, "ok")
+	expect(t, `"abc"`, cached.ETag, "cached.ETag")
+	expect(t, getBranchResponse, string(cached.Body), "cached.Body")
+	expect(t, "<http://example.org?page=2>; rel=\"next\"", cached.Header.Get("Link"), "cached.Header.Get(\"Link\")")
+
+	cache.Delete("key")
+	_, ok = cache.Get("key")
+	expect(t, false, ok, "ok")
+}
+
+func TestDiskCache_RevalidatesWithETag(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ghapi-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ts, requests := makeETagTestServer(t)
+	defer ts.Close()
+
+	cache, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a second GitHubAPI sharing the same directory gets the benefit of the first's cache
+	for i := 0; i < 2; i++ {
+		api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken, WithCache(cache))
+		branch, err := api.Branch.GetBranch("master")
+		expectNil(t, err, "err")
+		expect(t, "master", branch.Name, "branch.Name")
+		if i == 1 {
+			expect(t, int64(1), api.CacheStats().Hits, "api.CacheStats().Hits")
+		}
+	}
+
+	expect(t, int32(2), atomic.LoadInt32(requests), "requests")
+}
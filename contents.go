@@ -1,6 +1,7 @@
 package ghapi
 
 import (
+	"context"
 	"encoding/json"
 	neturl "net/url"
 )
@@ -29,19 +30,29 @@ type Contents struct {
 // is Base64 encoded. This API supports files up to 1MB in size.
 // See https://developer.github.com/v3/repos/contents/#get-contents
 func (api *ContentsAPI) GetContent(path string) (*Contents, error) {
-	return api.GetContentByRef(path, "")
+	return api.GetContentContext(context.Background(), path)
+}
+
+// GetContentContext is like GetContent but uses the provided context.
+func (api *ContentsAPI) GetContentContext(ctx context.Context, path string) (*Contents, error) {
+	return api.GetContentByRefContext(ctx, path, "")
 }
 
 // GetContentByRef gets the content for the specified path from the specified ref. The Contents.Content field
 // is Base64 encoded. This API supports files up to 1MB in size.
 // See https://developer.github.com/v3/repos/contents/#get-contents
 func (api *ContentsAPI) GetContentByRef(path, ref string) (*Contents, error) {
+	return api.GetContentByRefContext(context.Background(), path, ref)
+}
+
+// GetContentByRefContext is like GetContentByRef but uses the provided context.
+func (api *ContentsAPI) GetContentByRefContext(ctx context.Context, path, ref string) (*Contents, error) {
 	url := api.getURL("/repos/:owner/:repo/contents/") + path
 	if ref != "" {
 		url += "?ref=" + neturl.QueryEscape(ref)
 	}
 
-	resp, err := api.httpGet(url)
+	resp, err := api.httpGet(ctx, url)
 	if err != nil {
 		return nil, err
 	}
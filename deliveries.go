@@ -0,0 +1,257 @@
+package ghapi
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultDeliveryTTL is how long a DeliveryStore remembers a delivery by default. GitHub only allows deliveries from
+// the past 3 days to be redelivered.
+const DefaultDeliveryTTL = 72 * time.Hour
+
+// Delivery is a webhook event received from GitHub.
+type Delivery struct {
+	// ID is the "X-GitHub-Delivery" GUID. It's unchanged when GitHub redelivers an event.
+	ID string
+	// HookID is the "X-GitHub-Hook-ID" of the hook which sent the event, or 0 if the header is missing.
+	HookID int64
+	// EventType is the "X-Github-Event" type.
+	EventType GitHubEventType
+	// Header is the request's header.
+	Header http.Header
+	// Body is the event payload.
+	Body []byte
+
+	// failed, if set, is called by EventQueue when the delivery has failed every attempt.
+	failed func(err error)
+}
+
+// DeliveryStore records the delivery GUIDs which have been received, so redelivered events can be rejected.
+//
+// Implementations must be safe for concurrent use.
+type DeliveryStore interface {
+	// Seen records id and returns true if it was already recorded.
+	Seen(id string) (bool, error)
+	// Forget removes id so a redelivery will be accepted, for example after the event failed to process.
+	Forget(id string) error
+}
+
+// DeliveryReader reads webhook events, rejecting deliveries which have already been seen.
+type DeliveryReader struct {
+	// Policy validates event signatures.
+	Policy SignaturePolicy
+	// Store records the deliveries which have been read.
+	Store DeliveryStore
+}
+
+// ReadRequest reads and validates r. An error wrapping ErrDuplicateDelivery is returned if the delivery GUID has
+// already been seen. If the event can't be processed call Store.Forget with the delivery's ID so GitHub can redeliver
+// it.
+func (dr DeliveryReader) ReadRequest(r *http.Request) (*Delivery, error) {
+	delivery, err := dr.Policy.ReadDelivery(r)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkDelivery(dr.Store, delivery.ID); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// checkDelivery records id in store, returning an error wrapping ErrDuplicateDelivery if it was already recorded.
+func checkDelivery(store DeliveryStore, id string) error {
+	seen, err := store.Seen(id)
+	if err != nil {
+		return err
+	}
+	if seen {
+		return fmt.Errorf("%w: %s", ErrDuplicateDelivery, id)
+	}
+	return nil
+}
+
+// MemoryDeliveryStore is an in-memory DeliveryStore which forgets deliveries after a TTL.
+type MemoryDeliveryStore struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mtx   sync.Mutex
+	order *list.List
+	seen  map[string]*list.Element
+}
+
+type seenDelivery struct {
+	id        string
+	expiresAt time.Time
+}
+
+// NewMemoryDeliveryStore returns a MemoryDeliveryStore which remembers deliveries for ttl. If ttl is zero or less
+// DefaultDeliveryTTL is used.
+func NewMemoryDeliveryStore(ttl time.Duration) *MemoryDeliveryStore {
+	if ttl <= 0 {
+		ttl = DefaultDeliveryTTL
+	}
+	return &MemoryDeliveryStore{
+		ttl:   ttl,
+		now:   time.Now,
+		order: list.New(),
+		seen:  make(map[string]*list.Element),
+	}
+}
+
+// Seen records id and returns true if it was already recorded.
+func (s *MemoryDeliveryStore) Seen(id string) (bool, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := s.now()
+	s.expire(now)
+
+	if _, ok := s.seen[id]; ok {
+		return true, nil
+	}
+	s.seen[id] = s.order.PushBack(&seenDelivery{id: id, expiresAt: now.Add(s.ttl)})
+	return false, nil
+}
+
+// Forget removes id.
+func (s *MemoryDeliveryStore) Forget(id string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if elem, ok := s.seen[id]; ok {
+		s.order.Remove(elem)
+		delete(s.seen, id)
+	}
+	return nil
+}
+
+// Len returns the number of deliveries remembered.
+func (s *MemoryDeliveryStore) Len() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.expire(s.now())
+	return s.order.Len()
+}
+
+// expire removes expired deliveries. Every entry has the same TTL so the oldest entries are at the front.
+func (s *MemoryDeliveryStore) expire(now time.Time) {
+	for elem := s.order.Front(); elem != nil; elem = s.order.Front() {
+		d := elem.Value.(*seenDelivery)
+		if now.Before(d.expiresAt) {
+			return
+		}
+		s.order.Remove(elem)
+		delete(s.seen, d.id)
+	}
+}
+
+// FileDeliveryStore is a DeliveryStore which records each delivery as a file in a directory, so deliveries are
+// remembered across restarts and by multiple processes sharing the directory.
+type FileDeliveryStore struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+	mtx sync.Mutex
+}
+
+// NewFileDeliveryStore returns a FileDeliveryStore which records deliveries in dir and remembers them for ttl. The
+// directory is created if it doesn't exist. If ttl is zero or less DefaultDeliveryTTL is used.
+func NewFileDeliveryStore(dir string, ttl time.Duration) (*FileDeliveryStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	if ttl <= 0 {
+		ttl = DefaultDeliveryTTL
+	}
+	return &FileDeliveryStore{dir: dir, ttl: ttl, now: time.Now}, nil
+}
+
+// Seen records id and returns true if it was already recorded. Expired deliveries are treated as new.
+func (s *FileDeliveryStore) Seen(id string) (bool, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	name := s.filename(id)
+	now := s.now()
+
+	// O_EXCL makes the check and record atomic for processes sharing the directory
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if !os.IsExist(err) {
+			return false, err
+		}
+
+		fi, statErr := os.Stat(name)
+		if statErr != nil {
+			return false, statErr
+		}
+		if now.Before(fi.ModTime().Add(s.ttl)) {
+			return true, nil
+		}
+
+		// expired; record it again
+		return false, os.Chtimes(name, now, now)
+	}
+
+	_, err = f.Write([]byte(id))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(name)
+		return false, err
+	}
+
+	return false, os.Chtimes(name, now, now)
+}
+
+// Forget removes id.
+func (s *FileDeliveryStore) Forget(id string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if err := os.Remove(s.filename(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Prune removes the files of expired deliveries. Expired deliveries are ignored by Seen, so Prune only needs to be
+// called periodically to reclaim disk space.
+func (s *FileDeliveryStore) Prune() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	for _, fi := range files {
+		if fi.IsDir() || filepath.Ext(fi.Name()) != ".delivery" {
+			continue
+		}
+		if !now.Before(fi.ModTime().Add(s.ttl)) {
+			if err = os.Remove(filepath.Join(s.dir, fi.Name())); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *FileDeliveryStore) filename(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".delivery")
+}
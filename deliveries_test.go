@@ -0,0 +1,154 @@
+package ghapi
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testDeliveryID = "72d3162e-cc78-11e3-81ab-4c9367dc0958"
+
+func makeDeliveryRequest(t *testing.T, id string) *http.Request {
+	req := makeWebhookRequest(t, PushEventType, `{"ref":"refs/heads/master"}`)
+	req.Header.Set("X-GitHub-Delivery", id)
+	req.Header.Set("X-GitHub-Hook-ID", "292430182")
+	return req
+}
+
+func TestReadDelivery(t *testing.T) {
+	delivery, err := ReadDelivery(webhookTestSecret, makeDeliveryRequest(t, testDeliveryID))
+
+	expectNil(t, err, "err")
+	expect(t, testDeliveryID, delivery.ID, "delivery.ID")
+	expect(t, int64(292430182), delivery.HookID, "delivery.HookID")
+	expect(t, PushEventType, delivery.EventType, "delivery.EventType")
+	expect(t, `{"ref":"refs/heads/master"}`, string(delivery.Body), "delivery.Body")
+}
+
+func TestReadDelivery_FailsWhenDeliveryIDNotPresent(t *testing.T) {
+	req := makeDeliveryRequest(t, testDeliveryID)
+	req.Header.Del("X-GitHub-Delivery")
+
+	_, err := ReadDelivery(webhookTestSecret, req)
+	expect(t, ErrDeliveryIDNotFound, err, "err")
+}
+
+func TestGetHookID(t *testing.T) {
+	expect(t, int64(12), GetHookID(http.Header{"X-Github-Hook-Id": []string{"12"}}), "GetHookID(12)")
+	expect(t, int64(0), GetHookID(http.Header{}), "GetHookID(missing)")
+	expect(t, int64(0), GetHookID(http.Header{"X-Github-Hook-Id": []string{"abc"}}), "GetHookID(abc)")
+}
+
+func TestDeliveryReader_RejectsDuplicates(t *testing.T) {
+	dr := DeliveryReader{
+		Policy: SignaturePolicy{Secrets: [][]byte{webhookTestSecret}},
+		Store:  NewMemoryDeliveryStore(0),
+	}
+
+	_, err := dr.ReadRequest(makeDeliveryRequest(t, testDeliveryID))
+	expectNil(t, err, "err")
+
+	_, err = dr.ReadRequest(makeDeliveryRequest(t, testDeliveryID))
+	if !errors.Is(err, ErrDuplicateDelivery) {
+		t.Fatalf("expected ErrDuplicateDelivery, got %v", err)
+	}
+	if !strings.Contains(err.Error(), testDeliveryID) {
+		t.Fatalf("err %q doesn't contain delivery ID", err)
+	}
+
+	// a forgotten delivery is accepted again
+	expectNil(t, dr.Store.Forget(testDeliveryID), "Forget")
+	_, err = dr.ReadRequest(makeDeliveryRequest(t, testDeliveryID))
+	expectNil(t, err, "err")
+}
+
+func TestMemoryDeliveryStore_Expires(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	store := NewMemoryDeliveryStore(time.Hour)
+	store.now = func() time.Time { return now }
+
+	seen, err := store.Seen("a")
+	expectNil(t, err, "err")
+	expect(t, false, seen, "seen a")
+
+	now = now.Add(30 * time.Minute)
+	seen, _ = store.Seen("b")
+	expect(t, false, seen, "seen b")
+	seen, _ = store.Seen("a")
+	expect(t, true, seen, "seen a again")
+	expect(t, 2, store.Len(), "store.Len()")
+
+	now = now.Add(30 * time.Minute)
+	expect(t, 1, store.Len(), "store.Len() after a expired")
+	seen, _ = store.Seen("a")
+	expect(t, false, seen, "seen a after expiry")
+}
+
+func TestFileDeliveryStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ghapi-deliveries")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	store, err := NewFileDeliveryStore(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.now = func() time.Time { return now }
+
+	seen, err := store.Seen(testDeliveryID)
+	expectNil(t, err, "err")
+	expect(t, false, seen, "seen")
+
+	// a second store sharing the directory, ie: after a restart
+	other, err := NewFileDeliveryStore(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen, err = other.Seen(testDeliveryID)
+	expectNil(t, err, "err")
+	expect(t, true, seen, "seen by other store")
+
+	expectNil(t, store.Forget(testDeliveryID), "Forget")
+	expectNil(t, store.Forget(testDeliveryID), "Forget twice")
+	seen, _ = store.Seen(testDeliveryID)
+	expect(t, false, seen, "seen after Forget")
+
+	now = now.Add(2 * time.Hour)
+	expectNil(t, store.Prune(), "Prune")
+	files, err := ioutil.ReadDir(dir)
+	expectNil(t, err, "err")
+	expect(t, 0, len(files), "len(files) after Prune")
+}
+
+func TestFileDeliveryStore_Expires(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ghapi-deliveries")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	store, err := NewFileDeliveryStore(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.now = func() time.Time { return now }
+
+	seen, _ := store.Seen("a")
+	expect(t, false, seen, "seen")
+
+	now = now.Add(time.Hour)
+	seen, err = store.Seen("a")
+	expectNil(t, err, "err")
+	expect(t, false, seen, "seen after expiry")
+
+	seen, _ = store.Seen("a")
+	expect(t, true, seen, "seen after re-recording")
+}
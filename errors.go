@@ -1,12 +1,17 @@
 package ghapi
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // ErrHTTPError is returned when a non-200 status code is returned from a GitHub API call.
+//
+// Use errors.Is with ErrNotFound, ErrUnauthorized, ErrConflict or ErrValidationFailed to check the kind of error,
+// or errors.As to get the *ErrHTTPError from an *ErrRateLimited or *ErrSecondaryRateLimit.
 type ErrHTTPError struct {
 	Message      string
 	Status       string
@@ -15,6 +20,11 @@ type ErrHTTPError struct {
 	RequestBody  string
 	ResponseBody string
 	URL          string
+	// ErrorResponse is ResponseBody decoded. It's nil if ResponseBody isn't a GitHub error JSON object.
+	ErrorResponse *ErrorResponse
+	// RetryAfter is the delay requested by the response's "Retry-After" header, usually sent with a 503. It's zero
+	// if the header wasn't set.
+	RetryAfter time.Duration
 }
 
 func (e ErrHTTPError) Error() string {
@@ -23,7 +33,130 @@ func (e ErrHTTPError) Error() string {
 	return fmt.Sprintf("%s\n%s %s\nRequest Body:\n%s\nResponse Body:\n%s", message, e.Method, e.URL, e.RequestBody, e.ResponseBody)
 }
 
-// ErrSignatureNotFound is returned when the "X-Hub-Signature" header is not found in a GitHub event.
+// Is reports whether target is the sentinel error for e's status code. It allows errors.Is(err, ErrNotFound).
+func (e *ErrHTTPError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == 404
+	case ErrUnauthorized:
+		return e.StatusCode == 401
+	case ErrConflict:
+		return e.StatusCode == 409
+	case ErrValidationFailed:
+		return e.StatusCode == 422
+	}
+	return false
+}
+
+// HasErrorCode returns true if one of the errors in ErrorResponse has the specified code, for example "already_exists"
+// or "missing_field".
+func (e ErrHTTPError) HasErrorCode(code string) bool {
+	if e.ErrorResponse == nil {
+		return false
+	}
+	for _, fieldErr := range e.ErrorResponse.Errors {
+		if fieldErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorResponse is the JSON body returned by GitHub with an error status code.
+// See https://developer.github.com/v3/#client-errors.
+type ErrorResponse struct {
+	Message          string       `json:"message"`
+	Errors           []FieldError `json:"errors"`
+	DocumentationURL string       `json:"documentation_url"`
+}
+
+// FieldError describes a problem with a single field of a request. Code is one of "missing", "missing_field",
+// "invalid", "already_exists", "unprocessable" or "custom"; for "custom" Message describes the problem.
+type FieldError struct {
+	Resource string `json:"resource"`
+	Field    string `json:"field"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+}
+
+// UnmarshalJSON unmarshals a FieldError. Some endpoints return errors as plain strings, which are stored in Message.
+func (e *FieldError) UnmarshalJSON(b []byte) error {
+	var message string
+	if err := json.Unmarshal(b, &message); err == nil {
+		*e = FieldError{Message: message}
+		return nil
+	}
+
+	type fieldError FieldError
+	var raw fieldError
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*e = FieldError(raw)
+	return nil
+}
+
+// parseErrorResponse decodes a GitHub error body. It returns nil if body isn't a JSON object with a message.
+func parseErrorResponse(body string) *ErrorResponse {
+	var errResp ErrorResponse
+	if err := json.Unmarshal([]byte(body), &errResp); err != nil {
+		return nil
+	}
+	if errResp.Message == "" && len(errResp.Errors) == 0 {
+		return nil
+	}
+	return &errResp
+}
+
+// ErrNotFound matches an *ErrHTTPError with status code 404 when used with errors.Is.
+var ErrNotFound = errors.New("not found")
+
+// ErrUnauthorized matches an *ErrHTTPError with status code 401 when used with errors.Is.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// ErrConflict matches an *ErrHTTPError with status code 409 when used with errors.Is.
+var ErrConflict = errors.New("conflict")
+
+// ErrValidationFailed matches an *ErrHTTPError with status code 422 when used with errors.Is.
+var ErrValidationFailed = errors.New("validation failed")
+
+// ErrRateLimited is returned when the primary rate limit has been exceeded. Requests should not be retried
+// until Rate.Reset.
+// See https://developer.github.com/v3/#rate-limiting.
+type ErrRateLimited struct {
+	*ErrHTTPError
+	Rate Rate
+}
+
+func (e *ErrRateLimited) Error() string {
+	return fmt.Sprintf("rate limit of %d requests exceeded; resets at %s\n%s",
+		e.Rate.Limit, e.Rate.Reset.Format(time.RFC3339), e.ErrHTTPError.Error())
+}
+
+// Unwrap returns the underlying *ErrHTTPError.
+func (e *ErrRateLimited) Unwrap() error {
+	return e.ErrHTTPError
+}
+
+// ErrSecondaryRateLimit is returned when a secondary (abuse) rate limit has been triggered. Requests should not be
+// retried until RetryAfter has elapsed.
+// See https://developer.github.com/v3/#abuse-rate-limits.
+type ErrSecondaryRateLimit struct {
+	*ErrHTTPError
+	RetryAfter time.Duration
+}
+
+func (e *ErrSecondaryRateLimit) Error() string {
+	return fmt.Sprintf("secondary rate limit exceeded; retry after %v\n%s", e.RetryAfter, e.ErrHTTPError.Error())
+}
+
+// Unwrap returns the underlying *ErrHTTPError.
+func (e *ErrSecondaryRateLimit) Unwrap() error {
+	return e.ErrHTTPError
+}
+
+// ErrSignatureNotFound is returned when neither the "X-Hub-Signature" nor "X-Hub-Signature-256" header is found in a
+// GitHub event.
 var ErrSignatureNotFound = errors.New("\"X-Hub-Signature\" header not found")
 
 // ErrSignatureMismatch is returned when the "X-Hub-Signature" in a GitHub event does not match the expected signature.
@@ -36,5 +169,57 @@ var ErrGitHubEventNotFound = errors.New("\"X-Github-Event\" header not found")
 // a GitHub event.
 var ErrSignatureMarkerNotFound = errors.New("\"sha1=\" marker not found")
 
+// ErrSignature256MarkerNotFound is returned when the "sha256=" marker is not found in the "X-Hub-Signature-256" header
+// in a GitHub event.
+var ErrSignature256MarkerNotFound = errors.New("\"sha256=\" marker not found")
+
+// ErrMultipleSignatures is returned when a signature header appears more than once in a GitHub event.
+var ErrMultipleSignatures = errors.New("multiple signature headers found")
+
+// ErrSHA256SignatureRequired is returned when a SignaturePolicy requires SHA-256 but a GitHub event only has an
+// "X-Hub-Signature" header.
+var ErrSHA256SignatureRequired = errors.New("\"X-Hub-Signature-256\" header required")
+
+// ErrSignatureMalformed is returned when a signature header in a GitHub event isn't valid hex, or is the wrong length
+// for its algorithm.
+type ErrSignatureMalformed struct {
+	// Header is the name of the malformed header.
+	Header string
+	// Err describes the problem, for example a hex.InvalidByteError.
+	Err error
+}
+
+// Error returns the message of the underlying error.
+func (e *ErrSignatureMalformed) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ErrSignatureMalformed) Unwrap() error {
+	return e.Err
+}
+
 // ErrHTTPRequestBodyNil is returned when the request body is nil from a GitHub event.
 var ErrHTTPRequestBodyNil = errors.New("http.Request Body is nil")
+
+// ErrInvalidPrivateKey is returned by NewAppTokenSource when the private key is not a PEM encoded RSA private key.
+var ErrInvalidPrivateKey = errors.New("private key is not a PEM encoded RSA private key")
+
+// ErrTokenPoolEmpty is returned when a request is authenticated by a TokenPool with no tokens.
+var ErrTokenPoolEmpty = errors.New("token pool is empty")
+
+// ErrUnknownEventType is returned by ParsePayload for an event type without a payload struct.
+var ErrUnknownEventType = errors.New("unknown event type")
+
+// ErrDeliveryIDNotFound is returned when the "X-GitHub-Delivery" header is not found in a GitHub event.
+var ErrDeliveryIDNotFound = errors.New("\"X-GitHub-Delivery\" header not found")
+
+// ErrDuplicateDelivery is returned when a GitHub event with the same "X-GitHub-Delivery" GUID has already been
+// received. Use errors.Is to check for it; the returned error includes the delivery GUID.
+var ErrDuplicateDelivery = errors.New("duplicate delivery")
+
+// ErrQueueFull is returned by EventQueue.Enqueue when MaxPending events are already waiting.
+var ErrQueueFull = errors.New("event queue is full")
+
+// ErrQueueClosed is returned by EventQueue.Enqueue after Shutdown has been called.
+var ErrQueueClosed = errors.New("event queue is closed")
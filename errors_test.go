@@ -1,6 +1,11 @@
 package ghapi
 
-import "testing"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
 
 func TestErrHttpError_Error(t *testing.T) {
 	e := ErrHTTPError{
@@ -15,4 +20,98 @@ func TestErrHttpError_Error(t *testing.T) {
 	const expected string = "404 Not Found\nPOST http://example.org\nRequest Body:\n{ id: \"1\" }\nResponse Body:\n{ message: \"not found\" }"
 
 	expect(t, expected, e.Error(), "e.Error()")
+
+	// an ErrHTTPError value is an error, as well as a pointer to one
+	var err error = e
+	expect(t, expected, err.Error(), "err.Error()")
+}
+
+func TestErrHTTPError_DecodesErrorResponse(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(422)
+		_, err := w.Write([]byte(`{
+  "message": "Validation Failed",
+  "errors": [
+    {
+      "resource": "Issue",
+      "field": "title",
+      "code": "missing_field"
+    }
+  ],
+  "documentation_url": "https://developer.github.com/v3/issues/#create-an-issue"
+}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	defer ts.Close()
+
+	_, err := api.Repository.Get()
+	waitSignal(t, signal)
+
+	expect(t, true, IsValidationError(err), "IsValidationError(err)")
+	expect(t, true, errors.Is(err, ErrValidationFailed), "errors.Is(err, ErrValidationFailed)")
+	expect(t, false, errors.Is(err, ErrNotFound), "errors.Is(err, ErrNotFound)")
+
+	var httpErr *ErrHTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("err is not of type *ErrHTTPError, is %T", err)
+	}
+	expectNotNil(t, httpErr.ErrorResponse, "httpErr.ErrorResponse")
+	expect(t, "Validation Failed", httpErr.ErrorResponse.Message, "ErrorResponse.Message")
+	expect(t, "https://developer.github.com/v3/issues/#create-an-issue", httpErr.ErrorResponse.DocumentationURL, "ErrorResponse.DocumentationURL")
+	expect(t, 1, len(httpErr.ErrorResponse.Errors), "len(ErrorResponse.Errors)")
+	expect(t, FieldError{Resource: "Issue", Field: "title", Code: "missing_field"}, httpErr.ErrorResponse.Errors[0], "ErrorResponse.Errors[0]")
+	expect(t, true, httpErr.HasErrorCode("missing_field"), "HasErrorCode(\"missing_field\")")
+	expect(t, false, httpErr.HasErrorCode("already_exists"), "HasErrorCode(\"already_exists\")")
+}
+
+func TestErrHTTPError_StringErrors(t *testing.T) {
+	errResp := parseErrorResponse(`{"message":"Validation Failed","errors":["Reference already exists"]}`)
+
+	expectNotNil(t, errResp, "errResp")
+	expect(t, 1, len(errResp.Errors), "len(errResp.Errors)")
+	expect(t, "Reference already exists", errResp.Errors[0].Message, "errResp.Errors[0].Message")
+}
+
+func TestErrHTTPError_NonJSONBody(t *testing.T) {
+	expectNil(t, parseErrorResponse("<html>Bad Gateway</html>"), "parseErrorResponse(html)")
+	expectNil(t, parseErrorResponse(""), "parseErrorResponse(\"\")")
+	expectNil(t, parseErrorResponse(`{"name":"master"}`), "parseErrorResponse(non-error JSON)")
+}
+
+func TestIsHTTPError_StatusHelpers(t *testing.T) {
+	cases := []struct {
+		statusCode int
+		sentinel   error
+		is         func(error) bool
+	}{
+		{404, ErrNotFound, IsNotFound},
+		{401, ErrUnauthorized, IsUnauthorized},
+		{409, ErrConflict, IsConflict},
+		{422, ErrValidationFailed, IsValidationError},
+	}
+
+	for _, c := range cases {
+		err := &ErrHTTPError{StatusCode: c.statusCode}
+		wrapped := fmt.Errorf("getting repository: %w", err)
+
+		expect(t, true, c.is(err), fmt.Sprintf("%d: is(err)", c.statusCode))
+		expect(t, true, c.is(wrapped), fmt.Sprintf("%d: is(wrapped)", c.statusCode))
+		expect(t, true, IsHTTPError(wrapped, c.statusCode), fmt.Sprintf("%d: IsHTTPError(wrapped)", c.statusCode))
+		expect(t, true, errors.Is(wrapped, c.sentinel), fmt.Sprintf("%d: errors.Is(wrapped, sentinel)", c.statusCode))
+		expect(t, false, c.is(&ErrHTTPError{StatusCode: 500}), fmt.Sprintf("%d: is(500)", c.statusCode))
+	}
+
+	expect(t, false, IsHTTPError(nil, 404), "IsHTTPError(nil, 404)")
+	expect(t, false, IsHTTPError(errors.New("404"), 404), "IsHTTPError(errors.New(\"404\"), 404)")
+}
+
+func TestIsHTTPError_RateLimitErrors(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &ErrRateLimited{ErrHTTPError: &ErrHTTPError{StatusCode: 403}})
+
+	expect(t, true, IsHTTPError(err, 403), "IsHTTPError(err, 403)")
+
+	var rateErr *ErrRateLimited
+	expect(t, true, errors.As(err, &rateErr), "errors.As(err, &rateErr)")
 }
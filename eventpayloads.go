@@ -1,11 +1,20 @@
 package ghapi
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 // GitHubEventPayload contains Repository and Sender information. It's not sent by any GitHub events but is used
-// as an embedded type in other event payloads.
+// as an embedded type in other event payloads. Organization is set for events in an organization's repositories and
+// for organization hooks; Installation is set for events delivered to a GitHub App.
 type GitHubEventPayload struct {
-	Sender     User `json:"sender"`
+	Sender       User        `json:"sender"`
+	Organization *OrgSummary `json:"organization"`
+	Installation *struct {
+		ID int64 `json:"id"`
+	} `json:"installation"`
 	Repository struct {
 		ID               int       `json:"id"`
 		Name             string    `json:"name"`
@@ -130,7 +139,11 @@ type IssueCommentEventPayload struct {
 // PushEventPayload is received from the Push Event.
 // See https://developer.github.com/v3/activity/events/types/#pushevent.
 type PushEventPayload struct {
-	Sender  User    `json:"sender"`
+	Sender       User        `json:"sender"`
+	Organization *OrgSummary `json:"organization"`
+	Installation *struct {
+		ID int64 `json:"id"`
+	} `json:"installation"`
 	Ref     string  `json:"ref"`
 	Before  string  `json:"before"`
 	After   string  `json:"after"`
@@ -260,3 +273,393 @@ type PushEventPayload struct {
 		Email string `json:"email"`
 	} `json:"pusher"`
 }
+
+// EventChanges contains the previous values of fields changed by an "edited" action, keyed by field name. From is a
+// string for most fields.
+type EventChanges map[string]struct {
+	From interface{} `json:"from"`
+}
+
+// CommitCommentEventPayload is received from the Commit Comment Event.
+// See https://developer.github.com/v3/activity/events/types/#commitcommentevent.
+type CommitCommentEventPayload struct {
+	GitHubEventPayload
+	Action  string `json:"action"`
+	Comment struct {
+		URL       string    `json:"url"`
+		HTMLURL   string    `json:"html_url"`
+		ID        int       `json:"id"`
+		User      User      `json:"user"`
+		Position  *int      `json:"position"`
+		Line      *int      `json:"line"`
+		Path      *string   `json:"path"`
+		CommitID  string    `json:"commit_id"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+		Body      string    `json:"body"`
+	} `json:"comment"`
+}
+
+// CreateEventPayload is received from the Create Event.
+// See https://developer.github.com/v3/activity/events/types/#createevent.
+type CreateEventPayload struct {
+	GitHubEventPayload
+	Ref          string  `json:"ref"`
+	RefType      string  `json:"ref_type"`
+	MasterBranch string  `json:"master_branch"`
+	Description  *string `json:"description"`
+	PusherType   string  `json:"pusher_type"`
+}
+
+// DeleteEventPayload is received from the Delete Event.
+// See https://developer.github.com/v3/activity/events/types/#deleteevent.
+type DeleteEventPayload struct {
+	GitHubEventPayload
+	Ref        string `json:"ref"`
+	RefType    string `json:"ref_type"`
+	PusherType string `json:"pusher_type"`
+}
+
+// Deployment contains information about a deployment. This type is used in DeploymentEventPayload and
+// DeploymentStatusEventPayload.
+type Deployment struct {
+	URL           string          `json:"url"`
+	ID            int             `json:"id"`
+	SHA           string          `json:"sha"`
+	Ref           string          `json:"ref"`
+	Task          string          `json:"task"`
+	Payload       json.RawMessage `json:"payload"`
+	Environment   string          `json:"environment"`
+	Description   *string         `json:"description"`
+	Creator       User            `json:"creator"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	StatusesURL   string          `json:"statuses_url"`
+	RepositoryURL string          `json:"repository_url"`
+}
+
+// DeploymentEventPayload is received from the Deployment Event.
+// See https://developer.github.com/v3/activity/events/types/#deploymentevent.
+type DeploymentEventPayload struct {
+	GitHubEventPayload
+	Deployment Deployment `json:"deployment"`
+}
+
+// DeploymentStatusEventPayload is received from the Deployment Status Event.
+// See https://developer.github.com/v3/activity/events/types/#deploymentstatusevent.
+type DeploymentStatusEventPayload struct {
+	GitHubEventPayload
+	DeploymentStatus struct {
+		URL           string    `json:"url"`
+		ID            int       `json:"id"`
+		State         string    `json:"state"`
+		Creator       User      `json:"creator"`
+		Description   *string   `json:"description"`
+		TargetURL     *string   `json:"target_url"`
+		CreatedAt     time.Time `json:"created_at"`
+		UpdatedAt     time.Time `json:"updated_at"`
+		DeploymentURL string    `json:"deployment_url"`
+		RepositoryURL string    `json:"repository_url"`
+	} `json:"deployment_status"`
+	Deployment Deployment `json:"deployment"`
+}
+
+// ForkEventPayload is received from the Fork Event. Forkee is the newly created fork.
+// See https://developer.github.com/v3/activity/events/types/#forkevent.
+type ForkEventPayload struct {
+	GitHubEventPayload
+	Forkee RepositoryResponse `json:"forkee"`
+}
+
+// GollumEventPayload is received from the Gollum Event.
+// See https://developer.github.com/v3/activity/events/types/#gollumevent.
+type GollumEventPayload struct {
+	GitHubEventPayload
+	Pages []struct {
+		PageName string  `json:"page_name"`
+		Title    string  `json:"title"`
+		Summary  *string `json:"summary"`
+		Action   string  `json:"action"`
+		SHA      string  `json:"sha"`
+		HTMLURL  string  `json:"html_url"`
+	} `json:"pages"`
+}
+
+// IssuesEventPayload is received from the Issues Event. Label is set for "labeled" and "unlabeled" actions, and
+// Assignee for "assigned" and "unassigned" actions.
+// See https://developer.github.com/v3/activity/events/types/#issuesevent.
+type IssuesEventPayload struct {
+	GitHubEventPayload
+	Action   string        `json:"action"`
+	Issue    IssueResponse `json:"issue"`
+	Changes  EventChanges  `json:"changes"`
+	Label    *IssueLabel   `json:"label"`
+	Assignee *User         `json:"assignee"`
+}
+
+// LabelEventPayload is received from the Label Event.
+// See https://developer.github.com/v3/activity/events/types/#labelevent.
+type LabelEventPayload struct {
+	GitHubEventPayload
+	Action  string       `json:"action"`
+	Label   IssueLabel   `json:"label"`
+	Changes EventChanges `json:"changes"`
+}
+
+// MemberEventPayload is received from the Member Event.
+// See https://developer.github.com/v3/activity/events/types/#memberevent.
+type MemberEventPayload struct {
+	GitHubEventPayload
+	Action  string       `json:"action"`
+	Member  User         `json:"member"`
+	Changes EventChanges `json:"changes"`
+}
+
+// MembershipEventPayload is received from the Membership Event.
+// See https://developer.github.com/v3/activity/events/types/#membershipevent.
+type MembershipEventPayload struct {
+	GitHubEventPayload
+	Action string            `json:"action"`
+	Scope  string            `json:"scope"`
+	Member User              `json:"member"`
+	Team   ListTeamsResponse `json:"team"`
+}
+
+// MilestoneEventPayload is received from the Milestone Event.
+// See https://developer.github.com/v3/activity/events/types/#milestoneevent.
+type MilestoneEventPayload struct {
+	GitHubEventPayload
+	Action    string       `json:"action"`
+	Milestone Milestone    `json:"milestone"`
+	Changes   EventChanges `json:"changes"`
+}
+
+// PageBuildEventPayload is received from the Page Build Event.
+// See https://developer.github.com/v3/activity/events/types/#pagebuildevent.
+type PageBuildEventPayload struct {
+	GitHubEventPayload
+	ID    int `json:"id"`
+	Build struct {
+		URL    string `json:"url"`
+		Status string `json:"status"`
+		Error  struct {
+			Message *string `json:"message"`
+		} `json:"error"`
+		Pusher    User      `json:"pusher"`
+		Commit    string    `json:"commit"`
+		Duration  int       `json:"duration"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	} `json:"build"`
+}
+
+// PublicEventPayload is received from the Public Event.
+// See https://developer.github.com/v3/activity/events/types/#publicevent.
+type PublicEventPayload struct {
+	GitHubEventPayload
+}
+
+// PullRequestReviewEventPayload is received from the Pull Request Review Event.
+// See https://developer.github.com/v3/activity/events/types/#pullrequestreviewevent.
+type PullRequestReviewEventPayload struct {
+	GitHubEventPayload
+	Action string `json:"action"`
+	Review struct {
+		ID             int       `json:"id"`
+		User           User      `json:"user"`
+		Body           string    `json:"body"`
+		CommitID       string    `json:"commit_id"`
+		SubmittedAt    time.Time `json:"submitted_at"`
+		State          string    `json:"state"`
+		HTMLURL        string    `json:"html_url"`
+		PullRequestURL string    `json:"pull_request_url"`
+	} `json:"review"`
+	PullRequest PullRequestResponse `json:"pull_request"`
+}
+
+// PullRequestReviewCommentEventPayload is received from the Pull Request Review Comment Event.
+// See https://developer.github.com/v3/activity/events/types/#pullrequestreviewcommentevent.
+type PullRequestReviewCommentEventPayload struct {
+	GitHubEventPayload
+	Action  string `json:"action"`
+	Comment struct {
+		URL                 string    `json:"url"`
+		ID                  int       `json:"id"`
+		PullRequestReviewID int       `json:"pull_request_review_id"`
+		DiffHunk            string    `json:"diff_hunk"`
+		Path                string    `json:"path"`
+		Position            *int      `json:"position"`
+		OriginalPosition    int       `json:"original_position"`
+		CommitID            string    `json:"commit_id"`
+		OriginalCommitID    string    `json:"original_commit_id"`
+		User                User      `json:"user"`
+		Body                string    `json:"body"`
+		CreatedAt           time.Time `json:"created_at"`
+		UpdatedAt           time.Time `json:"updated_at"`
+		HTMLURL             string    `json:"html_url"`
+		PullRequestURL      string    `json:"pull_request_url"`
+	} `json:"comment"`
+	Changes     EventChanges        `json:"changes"`
+	PullRequest PullRequestResponse `json:"pull_request"`
+}
+
+// RepositoryEventPayload is received from the Repository Event.
+// See https://developer.github.com/v3/activity/events/types/#repositoryevent.
+type RepositoryEventPayload struct {
+	GitHubEventPayload
+	Action string `json:"action"`
+}
+
+// ReleaseEventPayload is received from the Release Event.
+// See https://developer.github.com/v3/activity/events/types/#releaseevent.
+type ReleaseEventPayload struct {
+	GitHubEventPayload
+	Action  string `json:"action"`
+	Release struct {
+		URL             string     `json:"url"`
+		AssetsURL       string     `json:"assets_url"`
+		UploadURL       string     `json:"upload_url"`
+		HTMLURL         string     `json:"html_url"`
+		ID              int        `json:"id"`
+		TagName         string     `json:"tag_name"`
+		TargetCommitish string     `json:"target_commitish"`
+		Name            *string    `json:"name"`
+		Draft           bool       `json:"draft"`
+		Author          User       `json:"author"`
+		Prerelease      bool       `json:"prerelease"`
+		CreatedAt       time.Time  `json:"created_at"`
+		PublishedAt     *time.Time `json:"published_at"`
+		Assets          []struct {
+			URL                string    `json:"url"`
+			BrowserDownloadURL string    `json:"browser_download_url"`
+			ID                 int       `json:"id"`
+			Name               string    `json:"name"`
+			Label              *string   `json:"label"`
+			State              string    `json:"state"`
+			ContentType        string    `json:"content_type"`
+			Size               int       `json:"size"`
+			DownloadCount      int       `json:"download_count"`
+			CreatedAt          time.Time `json:"created_at"`
+			UpdatedAt          time.Time `json:"updated_at"`
+			Uploader           User      `json:"uploader"`
+		} `json:"assets"`
+		TarballURL string  `json:"tarball_url"`
+		ZipballURL string  `json:"zipball_url"`
+		Body       *string `json:"body"`
+	} `json:"release"`
+}
+
+// StatusEventPayload is received from the Status Event.
+// See https://developer.github.com/v3/activity/events/types/#statusevent.
+type StatusEventPayload struct {
+	GitHubEventPayload
+	ID          int         `json:"id"`
+	SHA         string      `json:"sha"`
+	Name        string      `json:"name"`
+	TargetURL   *string     `json:"target_url"`
+	Context     string      `json:"context"`
+	Description *string     `json:"description"`
+	State       StatusState `json:"state"`
+	Commit      struct {
+		SHA    string `json:"sha"`
+		Commit struct {
+			Author struct {
+				Name  string    `json:"name"`
+				Email string    `json:"email"`
+				Date  time.Time `json:"date"`
+			} `json:"author"`
+			Committer struct {
+				Name  string    `json:"name"`
+				Email string    `json:"email"`
+				Date  time.Time `json:"date"`
+			} `json:"committer"`
+			Message      string `json:"message"`
+			URL          string `json:"url"`
+			CommentCount int    `json:"comment_count"`
+		} `json:"commit"`
+		URL         string `json:"url"`
+		HTMLURL     string `json:"html_url"`
+		CommentsURL string `json:"comments_url"`
+		Author      *User  `json:"author"`
+		Committer   *User  `json:"committer"`
+	} `json:"commit"`
+	Branches []struct {
+		Name   string `json:"name"`
+		Commit struct {
+			SHA string `json:"sha"`
+			URL string `json:"url"`
+		} `json:"commit"`
+	} `json:"branches"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TeamEventPayload is received from the Team Event. Repository is set for "added_to_repository" and
+// "removed_from_repository" actions.
+// See https://developer.github.com/v3/activity/events/types/#teamevent.
+type TeamEventPayload struct {
+	GitHubEventPayload
+	Action  string            `json:"action"`
+	Team    ListTeamsResponse `json:"team"`
+	Changes EventChanges      `json:"changes"`
+}
+
+// TeamAddEventPayload is received from the Team Add Event.
+// See https://developer.github.com/v3/activity/events/types/#teamaddevent.
+type TeamAddEventPayload struct {
+	GitHubEventPayload
+	Team ListTeamsResponse `json:"team"`
+}
+
+// WatchEventPayload is received from the Watch Event. Action is always "started".
+// See https://developer.github.com/v3/activity/events/types/#watchevent.
+type WatchEventPayload struct {
+	GitHubEventPayload
+	Action string `json:"action"`
+}
+
+// eventPayloads returns a new, empty payload for each event type.
+var eventPayloads = map[GitHubEventType]func() interface{}{
+	CommitCommentEventType:            func() interface{} { return &CommitCommentEventPayload{} },
+	CreateEventType:                   func() interface{} { return &CreateEventPayload{} },
+	DeleteEventType:                   func() interface{} { return &DeleteEventPayload{} },
+	DeploymentEventType:               func() interface{} { return &DeploymentEventPayload{} },
+	DeploymentStatusEventType:         func() interface{} { return &DeploymentStatusEventPayload{} },
+	ForkEventType:                     func() interface{} { return &ForkEventPayload{} },
+	GollumEventType:                   func() interface{} { return &GollumEventPayload{} },
+	IssueCommentEventType:             func() interface{} { return &IssueCommentEventPayload{} },
+	IssuesEventType:                   func() interface{} { return &IssuesEventPayload{} },
+	LabelEventType:                    func() interface{} { return &LabelEventPayload{} },
+	MemberEventType:                   func() interface{} { return &MemberEventPayload{} },
+	MembershipEventType:               func() interface{} { return &MembershipEventPayload{} },
+	MilestoneEventType:                func() interface{} { return &MilestoneEventPayload{} },
+	PageBuildEventType:                func() interface{} { return &PageBuildEventPayload{} },
+	PublicEventType:                   func() interface{} { return &PublicEventPayload{} },
+	PullRequestReviewCommentEventType: func() interface{} { return &PullRequestReviewCommentEventPayload{} },
+	PullRequestReviewEventType:        func() interface{} { return &PullRequestReviewEventPayload{} },
+	PullRequestEventType:              func() interface{} { return &PullRequestEventPayload{} },
+	PushEventType:                     func() interface{} { return &PushEventPayload{} },
+	RepositoryEventType:               func() interface{} { return &RepositoryEventPayload{} },
+	ReleaseEventType:                  func() interface{} { return &ReleaseEventPayload{} },
+	StatusEventType:                   func() interface{} { return &StatusEventPayload{} },
+	TeamEventType:                     func() interface{} { return &TeamEventPayload{} },
+	TeamAddEventType:                  func() interface{} { return &TeamAddEventPayload{} },
+	WatchEventType:                    func() interface{} { return &WatchEventPayload{} },
+	PingEventType:                     func() interface{} { return &PingEventPayload{} },
+}
+
+// ParsePayload unmarshals body into the payload struct for eventType and returns a pointer to it, for example
+// *PushEventPayload for PushEventType. Use a type switch on the result to handle each event. An error wrapping
+// ErrUnknownEventType is returned for event types without a payload struct.
+func ParsePayload(eventType GitHubEventType, body []byte) (interface{}, error) {
+	newPayload, ok := eventPayloads[eventType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEventType, eventType)
+	}
+
+	payload := newPayload()
+	if err := json.Unmarshal(body, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
@@ -0,0 +1,176 @@
+package ghapi
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func readEventFixture(t *testing.T, eventType GitHubEventType) []byte {
+	body, err := ioutil.ReadFile(filepath.Join("testdata", "events", string(eventType)+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestParsePayload(t *testing.T) {
+	cases := []struct {
+		eventType GitHubEventType
+		check     func(t *testing.T, payload interface{})
+	}{
+		{CommitCommentEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*CommitCommentEventPayload)
+			expect(t, "created", p.Action, "Action")
+			expect(t, 11056394, p.Comment.ID, "Comment.ID")
+		}},
+		{CreateEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*CreateEventPayload)
+			expect(t, "0.0.1", p.Ref, "Ref")
+			expect(t, "tag", p.RefType, "RefType")
+		}},
+		{DeleteEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*DeleteEventPayload)
+			expect(t, "simple-tag", p.Ref, "Ref")
+		}},
+		{DeploymentEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*DeploymentEventPayload)
+			expect(t, 710692, p.Deployment.ID, "Deployment.ID")
+			expect(t, "production", p.Deployment.Environment, "Deployment.Environment")
+		}},
+		{DeploymentStatusEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*DeploymentStatusEventPayload)
+			expect(t, "success", p.DeploymentStatus.State, "DeploymentStatus.State")
+			expect(t, 710692, p.Deployment.ID, "Deployment.ID")
+		}},
+		{ForkEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*ForkEventPayload)
+			expect(t, "baxterandthehackers/public-repo", p.Forkee.FullName, "Forkee.FullName")
+			expect(t, true, p.Forkee.Fork, "Forkee.Fork")
+		}},
+		{GollumEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*GollumEventPayload)
+			expect(t, 1, len(p.Pages), "len(Pages)")
+			expect(t, "created", p.Pages[0].Action, "Pages[0].Action")
+		}},
+		{IssueCommentEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*IssueCommentEventPayload)
+			expect(t, "created", p.Action, "Action")
+			expect(t, 2, p.Issue.Number, "Issue.Number")
+		}},
+		{IssuesEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*IssuesEventPayload)
+			expect(t, "labeled", p.Action, "Action")
+			expectNotNil(t, p.Label, "Label")
+			expect(t, "bug", p.Label.Name, "Label.Name")
+		}},
+		{LabelEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*LabelEventPayload)
+			expect(t, "edited", p.Action, "Action")
+			expect(t, "e11d21", p.Changes["color"].From, `Changes["color"].From`)
+		}},
+		{MemberEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*MemberEventPayload)
+			expect(t, "octocat", p.Member.Login, "Member.Login")
+		}},
+		{MembershipEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*MembershipEventPayload)
+			expect(t, "contractors", p.Team.Slug, "Team.Slug")
+			expectNotNil(t, p.Organization, "Organization")
+			expect(t, "baxterandthehackers", p.Organization.Login, "Organization.Login")
+		}},
+		{MilestoneEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*MilestoneEventPayload)
+			expect(t, 3, p.Milestone.Number, "Milestone.Number")
+			expect(t, "Test milestone", p.Milestone.Title, "Milestone.Title")
+		}},
+		{PageBuildEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*PageBuildEventPayload)
+			expect(t, "built", p.Build.Status, "Build.Status")
+		}},
+		{PingEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*PingEventPayload)
+			expect(t, 12, p.HookID, "HookID")
+			expect(t, "https://example.org/webhook", p.Hook.Config.URL, "Hook.Config.URL")
+		}},
+		{PublicEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*PublicEventPayload)
+			expect(t, "baxterthehacker/public-repo", p.Repository.FullName, "Repository.FullName")
+		}},
+		{PullRequestEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*PullRequestEventPayload)
+			expect(t, Opened, p.Action, "Action")
+			expect(t, 1, p.Number, "Number")
+		}},
+		{PullRequestReviewEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*PullRequestReviewEventPayload)
+			expect(t, "approved", p.Review.State, "Review.State")
+			expect(t, 8, p.PullRequest.Number, "PullRequest.Number")
+		}},
+		{PullRequestReviewCommentEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*PullRequestReviewCommentEventPayload)
+			expect(t, "README.md", p.Comment.Path, "Comment.Path")
+			expect(t, 1, p.PullRequest.Number, "PullRequest.Number")
+		}},
+		{PushEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*PushEventPayload)
+			expect(t, "refs/heads/changes", p.Ref, "Ref")
+			expect(t, 1, len(p.Commits), "len(Commits)")
+		}},
+		{RepositoryEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*RepositoryEventPayload)
+			expect(t, "created", p.Action, "Action")
+			expect(t, true, p.Repository.Private, "Repository.Private")
+		}},
+		{ReleaseEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*ReleaseEventPayload)
+			expect(t, "0.0.1", p.Release.TagName, "Release.TagName")
+		}},
+		{StatusEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*StatusEventPayload)
+			expect(t, Success, p.State, "State")
+			expect(t, "master", p.Branches[0].Name, "Branches[0].Name")
+		}},
+		{TeamEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*TeamEventPayload)
+			expect(t, "added_to_repository", p.Action, "Action")
+			expect(t, "public-repo", p.Repository.Name, "Repository.Name")
+		}},
+		{TeamAddEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*TeamAddEventPayload)
+			expect(t, "Contractors", p.Team.Name, "Team.Name")
+		}},
+		{WatchEventType, func(t *testing.T, payload interface{}) {
+			p := payload.(*WatchEventPayload)
+			expect(t, "started", p.Action, "Action")
+			expect(t, "baxterthehacker", p.Sender.Login, "Sender.Login")
+		}},
+	}
+
+	for _, c := range cases {
+		payload, err := ParsePayload(c.eventType, readEventFixture(t, c.eventType))
+		if err != nil {
+			t.Fatalf("%s: %v", c.eventType, err)
+		}
+
+		expectedType := fmt.Sprintf("%T", eventPayloads[c.eventType]())
+		expect(t, expectedType, fmt.Sprintf("%T", payload), string(c.eventType))
+		c.check(t, payload)
+	}
+
+	expect(t, len(eventPayloads), len(cases), "len(cases)")
+}
+
+func TestParsePayload_UnknownEventType(t *testing.T) {
+	_, err := ParsePayload("gist", []byte(`{}`))
+	if !errors.Is(err, ErrUnknownEventType) {
+		t.Fatalf("expected ErrUnknownEventType, got %v", err)
+	}
+}
+
+func TestParsePayload_InvalidJSON(t *testing.T) {
+	_, err := ParsePayload(PushEventType, []byte(`{"ref":`))
+	expectNotNil(t, err, "err")
+}
@@ -3,15 +3,53 @@ package ghapi
 import (
 	"crypto/hmac"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"hash"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 // ReadRequest takes an expected GitHub webhook secret and an *http.Request. If signature validation succeeds the
 // GitHubEventType and request body as a byte slice are returned.
 func ReadRequest(secret []byte, r *http.Request) (GitHubEventType, []byte, error) {
+	return SignaturePolicy{Secrets: [][]byte{secret}}.ReadRequest(r)
+}
+
+// ReadDelivery is like ReadRequest but returns a Delivery, which includes the delivery GUID and hook ID.
+func ReadDelivery(secret []byte, r *http.Request) (*Delivery, error) {
+	return SignaturePolicy{Secrets: [][]byte{secret}}.ReadDelivery(r)
+}
+
+// ValidateEvent takes an expected GitHub webhook secret, a body, and an http.Header. If signature validation succeeds
+// nil is returned. The "X-Hub-Signature-256" header is checked if present, otherwise "X-Hub-Signature".
+//
+// This function may be used when it's necessary to inspect the body of an event before validating its signature; for
+// example, if the webhook stores separate secrets per organization but has a single endpoint to receive GitHub events
+// it can extract the organization to retrieve the appropriate secret.
+func ValidateEvent(secret []byte, body []byte, header http.Header) error {
+	return SignaturePolicy{Secrets: [][]byte{secret}}.ValidateEvent(body, header)
+}
+
+// SignaturePolicy controls how webhook signatures are validated.
+//
+// GitHub sends an HMAC-SHA1 signature in "X-Hub-Signature" and an HMAC-SHA256 signature in "X-Hub-Signature-256".
+// When both are present only the SHA-256 signature is checked.
+// See https://developer.github.com/webhooks/securing/.
+type SignaturePolicy struct {
+	// Secrets are the accepted webhook secrets. An event signed with any of them is valid, which allows a secret to
+	// be rotated without dropping events: add the new secret, update the hook, then remove the old secret.
+	Secrets [][]byte
+	// RequireSHA256 rejects events without an "X-Hub-Signature-256" header with ErrSHA256SignatureRequired.
+	RequireSHA256 bool
+}
+
+// ReadRequest reads the body of r and validates its signature. If validation succeeds the GitHubEventType and request
+// body as a byte slice are returned.
+func (p SignaturePolicy) ReadRequest(r *http.Request) (GitHubEventType, []byte, error) {
 	var body []byte
 	var err error
 	var eventType GitHubEventType
@@ -24,7 +62,7 @@ func ReadRequest(secret []byte, r *http.Request) (GitHubEventType, []byte, error
 		return "", nil, err
 	}
 
-	if err = ValidateEvent(secret, body, r.Header); err != nil {
+	if err = p.ValidateEvent(body, r.Header); err != nil {
 		return "", nil, err
 	}
 
@@ -35,30 +73,57 @@ func ReadRequest(secret []byte, r *http.Request) (GitHubEventType, []byte, error
 	return eventType, body, nil
 }
 
-// ValidateEvent takes an expected GitHub webhook secret, a body, and an http.Header. If signature validation succeeds
-// nil is returned.
-//
-// This function may be used when it's necessary to inspect the body of an event before validating its signature; for
-// example, if the webhook stores separate secrets per organization but has a single endpoint to receive GitHub events
-// it can extract the organization to retrieve the appropriate secret.
-func ValidateEvent(secret []byte, body []byte, header http.Header) error {
-	var err error
+// ReadDelivery is like ReadRequest but also returns the delivery GUID and hook ID. An error is returned if the
+// "X-GitHub-Delivery" header is missing.
+func (p SignaturePolicy) ReadDelivery(r *http.Request) (*Delivery, error) {
+	eventType, body, err := p.ReadRequest(r)
+	if err != nil {
+		return nil, err
+	}
 
+	id, err := GetDeliveryID(r.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Delivery{
+		ID:        id,
+		HookID:    GetHookID(r.Header),
+		EventType: eventType,
+		Header:    r.Header,
+		Body:      body,
+	}, nil
+}
+
+// ValidateEvent validates the signature of body. If validation succeeds nil is returned.
+func (p SignaturePolicy) ValidateEvent(body []byte, header http.Header) error {
 	if body == nil {
 		return ErrHTTPRequestBodyNil
 	}
 
-	var messageMAC []byte
-	if messageMAC, err = getMAC(header); err != nil {
+	alg := sha256Signature
+	if _, ok := header[alg.header]; !ok {
+		if p.RequireSHA256 {
+			if _, ok = header[sha1Signature.header]; ok {
+				return ErrSHA256SignatureRequired
+			}
+			return ErrSignatureNotFound
+		}
+		alg = sha1Signature
+	}
+
+	messageMAC, err := alg.getMAC(header)
+	if err != nil {
 		return err
 	}
 
-	sha1hmac := hmac.New(sha1.New, secret)
-	if ok := checkMAC(sha1hmac, body, messageMAC); !ok {
-		return ErrSignatureMismatch
+	for _, secret := range p.Secrets {
+		if checkMAC(hmac.New(alg.hash, secret), body, messageMAC) {
+			return nil
+		}
 	}
 
-	return nil
+	return ErrSignatureMismatch
 }
 
 // GetEventType returns the GitHubEventType based on the "X-Github-Event" header value from a received webhook request.
@@ -70,27 +135,82 @@ func GetEventType(header http.Header) (GitHubEventType, error) {
 	return GitHubEventType(events[0]), nil
 }
 
-func getSignature(header http.Header) (string, error) {
-	sigs := header["X-Hub-Signature"]
-	if sigs == nil || len(sigs) != 1 {
+// GetDeliveryID returns the "X-GitHub-Delivery" header value from a received webhook request. The GUID identifies
+// the delivery and is unchanged when GitHub redelivers an event.
+func GetDeliveryID(header http.Header) (string, error) {
+	ids := header["X-Github-Delivery"]
+	if len(ids) != 1 || ids[0] == "" {
+		return "", ErrDeliveryIDNotFound
+	}
+	return ids[0], nil
+}
+
+// GetHookID returns the "X-GitHub-Hook-ID" header value from a received webhook request, or 0 if the header is
+// missing or not a number.
+func GetHookID(header http.Header) int64 {
+	id, err := strconv.ParseInt(header.Get("X-GitHub-Hook-ID"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// signatureAlgorithm describes a webhook signature header.
+type signatureAlgorithm struct {
+	header         string
+	marker         string
+	hash           func() hash.Hash
+	size           int
+	markerNotFound error
+}
+
+var (
+	sha1Signature = signatureAlgorithm{
+		header:         "X-Hub-Signature",
+		marker:         "sha1=",
+		hash:           sha1.New,
+		size:           sha1.Size,
+		markerNotFound: ErrSignatureMarkerNotFound,
+	}
+	sha256Signature = signatureAlgorithm{
+		header:         "X-Hub-Signature-256",
+		marker:         "sha256=",
+		hash:           sha256.New,
+		size:           sha256.Size,
+		markerNotFound: ErrSignature256MarkerNotFound,
+	}
+)
+
+func (alg signatureAlgorithm) getSignature(header http.Header) (string, error) {
+	sigs := header[alg.header]
+	if len(sigs) == 0 {
 		return "", ErrSignatureNotFound
 	}
+	if len(sigs) != 1 {
+		return "", ErrMultipleSignatures
+	}
 	sig := sigs[0]
-	if sig[:5] != "sha1=" {
-		return "", ErrSignatureMarkerNotFound
+	if !strings.HasPrefix(sig, alg.marker) {
+		return "", alg.markerNotFound
 	}
-	return sig[5:], nil
+	return sig[len(alg.marker):], nil
 }
 
-func getMAC(header http.Header) ([]byte, error) {
-	sig, err := getSignature(header)
+func (alg signatureAlgorithm) getMAC(header http.Header) ([]byte, error) {
+	sig, err := alg.getSignature(header)
 	if err != nil {
 		return nil, err
 	}
 
 	messageMAC, err := hex.DecodeString(sig)
 	if err != nil {
-		return nil, err
+		return nil, &ErrSignatureMalformed{Header: alg.header, Err: err}
+	}
+	if len(messageMAC) != alg.size {
+		return nil, &ErrSignatureMalformed{
+			Header: alg.header,
+			Err:    fmt.Errorf("signature is %d bytes, expected %d", len(messageMAC), alg.size),
+		}
 	}
 
 	return messageMAC, nil
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"crypto/hmac"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -287,3 +289,140 @@ func TestReadRequest_MatchesExpected(t *testing.T) {
 
 	waitSignal(t, signal)
 }
+
+func signTestBody(t *testing.T, secret, body []byte) (sha1Sig, sha256Sig string) {
+	sha1hmac := hmac.New(sha1.New, secret)
+	sha256hmac := hmac.New(sha256.New, secret)
+	if _, err := sha1hmac.Write(body); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sha256hmac.Write(body); err != nil {
+		t.Fatal(err)
+	}
+	return "sha1=" + hex.EncodeToString(sha1hmac.Sum(nil)), "sha256=" + hex.EncodeToString(sha256hmac.Sum(nil))
+}
+
+func TestValidateEvent_SHA256(t *testing.T) {
+	secret := []byte("key")
+	body := []byte("message")
+	_, sha256Sig := signTestBody(t, secret, body)
+
+	header := http.Header{}
+	header.Set("X-Hub-Signature-256", sha256Sig)
+
+	expectNil(t, ValidateEvent(secret, body, header), "ValidateEvent")
+	expect(t, ErrSignatureMismatch, ValidateEvent([]byte("wrong"), body, header), "ValidateEvent(wrong secret)")
+}
+
+func TestValidateEvent_PrefersSHA256(t *testing.T) {
+	secret := []byte("key")
+	body := []byte("message")
+	sha1Sig, sha256Sig := signTestBody(t, secret, body)
+	_, wrongSHA256Sig := signTestBody(t, []byte("wrong"), body)
+
+	header := http.Header{}
+	header.Set("X-Hub-Signature", sha1Sig)
+	header.Set("X-Hub-Signature-256", sha256Sig)
+	expectNil(t, ValidateEvent(secret, body, header), "ValidateEvent")
+
+	// a valid SHA-1 signature doesn't rescue an invalid SHA-256 signature
+	header.Set("X-Hub-Signature-256", wrongSHA256Sig)
+	expect(t, ErrSignatureMismatch, ValidateEvent(secret, body, header), "ValidateEvent")
+}
+
+func TestSignaturePolicy_RequireSHA256(t *testing.T) {
+	secret := []byte("key")
+	body := []byte("message")
+	sha1Sig, sha256Sig := signTestBody(t, secret, body)
+	policy := SignaturePolicy{Secrets: [][]byte{secret}, RequireSHA256: true}
+
+	header := http.Header{}
+	header.Set("X-Hub-Signature", sha1Sig)
+	expect(t, ErrSHA256SignatureRequired, policy.ValidateEvent(body, header), "ValidateEvent(sha1 only)")
+	expect(t, ErrSignatureNotFound, policy.ValidateEvent(body, http.Header{}), "ValidateEvent(no signature)")
+
+	header.Set("X-Hub-Signature-256", sha256Sig)
+	expectNil(t, policy.ValidateEvent(body, header), "ValidateEvent(sha256)")
+}
+
+func TestSignaturePolicy_MultipleSecrets(t *testing.T) {
+	body := []byte("message")
+	policy := SignaturePolicy{Secrets: [][]byte{[]byte("old"), []byte("new")}}
+
+	for _, secret := range []string{"old", "new"} {
+		sha1Sig, sha256Sig := signTestBody(t, []byte(secret), body)
+
+		header := http.Header{}
+		header.Set("X-Hub-Signature", sha1Sig)
+		expectNil(t, policy.ValidateEvent(body, header), secret+": ValidateEvent(sha1)")
+
+		header.Set("X-Hub-Signature-256", sha256Sig)
+		expectNil(t, policy.ValidateEvent(body, header), secret+": ValidateEvent(sha256)")
+	}
+
+	sha1Sig, _ := signTestBody(t, []byte("other"), body)
+	header := http.Header{}
+	header.Set("X-Hub-Signature", sha1Sig)
+	expect(t, ErrSignatureMismatch, policy.ValidateEvent(body, header), "ValidateEvent(other)")
+}
+
+func TestSignaturePolicy_ReadRequest(t *testing.T) {
+	secret := []byte("key")
+	_, sha256Sig := signTestBody(t, secret, []byte("message"))
+
+	req, err := http.NewRequest("POST", "http://asdf", strings.NewReader("message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("X-Hub-Signature-256", sha256Sig)
+	req.Header.Add("X-Github-Event", "push")
+
+	eventType, body, err := SignaturePolicy{Secrets: [][]byte{secret}, RequireSHA256: true}.ReadRequest(req)
+
+	expectNil(t, err, "err")
+	expect(t, PushEventType, eventType, "eventType")
+	expect(t, "message", string(body), "body")
+}
+
+func TestValidateEvent_MalformedHeaders(t *testing.T) {
+	secret := []byte("key")
+	body := []byte("message")
+
+	cases := []struct {
+		header   string
+		value    string
+		expected error
+	}{
+		{"X-Hub-Signature", "", ErrSignatureMarkerNotFound},
+		{"X-Hub-Signature", "sha", ErrSignatureMarkerNotFound},
+		{"X-Hub-Signature", "sha256=2088df74d5f2146b48146caf4965377e9d0be3a4", ErrSignatureMarkerNotFound},
+		{"X-Hub-Signature-256", "sha1=2088df74d5f2146b48146caf4965377e9d0be3a4", ErrSignature256MarkerNotFound},
+		{"X-Hub-Signature-256", "sha2", ErrSignature256MarkerNotFound},
+	}
+
+	for _, c := range cases {
+		header := http.Header{}
+		header.Set(c.header, c.value)
+		expect(t, c.expected, ValidateEvent(secret, body, header), fmt.Sprintf("%s: %q", c.header, c.value))
+	}
+
+	header := http.Header{}
+	header.Add("X-Hub-Signature", "sha1=2088df74d5f2146b48146caf4965377e9d0be3a4")
+	header.Add("X-Hub-Signature", "sha1=2088df74d5f2146b48146caf4965377e9d0be3a4")
+	expect(t, ErrMultipleSignatures, ValidateEvent(secret, body, header), "ValidateEvent(multiple)")
+}
+
+func TestValidateEvent_SignatureMalformed(t *testing.T) {
+	for _, value := range []string{"sha256=zz", "sha256=2088df74d5f2146b48146caf4965377e9d0be3a4"} {
+		header := http.Header{}
+		header.Set("X-Hub-Signature-256", value)
+
+		err := ValidateEvent([]byte("key"), []byte("message"), header)
+
+		var malformed *ErrSignatureMalformed
+		if !errors.As(err, &malformed) {
+			t.Fatalf("%q: err is not of type *ErrSignatureMalformed, is %T", value, err)
+		}
+		expect(t, "X-Hub-Signature-256", malformed.Header, "malformed.Header")
+	}
+}
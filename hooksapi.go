@@ -0,0 +1,491 @@
+package ghapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// HooksAPI is used to create and manage the webhooks of a repository or organization. GitHubAPI.Hooks manages the
+// repository's hooks and GitHubAPI.OrganizationHooks manages the owner's organization hooks.
+type HooksAPI struct {
+	RepositoryInfo
+	// organization is true for the hooks of the organization named by Owner, rather than the repository's hooks.
+	organization bool
+}
+
+// HookConfig is a webhook's delivery configuration.
+type HookConfig struct {
+	// URL is the payload URL events are delivered to.
+	URL string `json:"url"`
+	// ContentType is "json" or "form". GitHub defaults to "form"; ReadRequest and WebhookServer expect "json".
+	ContentType string `json:"content_type,omitempty"`
+	// Secret signs each delivery. GitHub returns "********" in place of the secret when it's set.
+	Secret string `json:"secret,omitempty"`
+	// InsecureSSL is "1" to skip verifying the payload URL's TLS certificate.
+	InsecureSSL string `json:"insecure_ssl,omitempty"`
+}
+
+// Hook contains webhook information. This value is returned by HooksAPI.
+type Hook struct {
+	ID           int64      `json:"id"`
+	Type         string     `json:"type"`
+	Name         string     `json:"name"`
+	Active       bool       `json:"active"`
+	Events       []string   `json:"events"`
+	Config       HookConfig `json:"config"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	URL          string     `json:"url"`
+	TestURL      string     `json:"test_url"`
+	PingURL      string     `json:"ping_url"`
+	LastResponse struct {
+		Code    *int    `json:"code"`
+		Status  string  `json:"status"`
+		Message *string `json:"message"`
+	} `json:"last_response"`
+}
+
+// HookRequest is the body of HooksAPI.Create and HooksAPI.Edit. Fields left as their zero value are not changed by
+// Edit.
+type HookRequest struct {
+	// Name must be "web" for webhooks. Create sets it if empty.
+	Name string `json:"name,omitempty"`
+	// Config is the delivery configuration. Editing the config replaces all of it, including the secret.
+	Config *HookConfig `json:"config,omitempty"`
+	// Events are the events the hook is triggered for. GitHub defaults to "push"; use "*" for all events.
+	Events []string `json:"events,omitempty"`
+	// Active determines whether events are delivered. GitHub defaults to true.
+	Active *bool `json:"active,omitempty"`
+}
+
+// hooksURL returns the URL of the repository's or organization's hooks endpoint followed by suffix.
+func (api *HooksAPI) hooksURL(suffix string) string {
+	if api.organization {
+		return api.getURL("/orgs/:owner/hooks" + suffix)
+	}
+	return api.getURL("/repos/:owner/:repo/hooks" + suffix)
+}
+
+func (api *HooksAPI) hookURL(hookID int64, suffix string) string {
+	return api.hooksURL("/" + strconv.FormatInt(hookID, 10) + suffix)
+}
+
+// Create creates a webhook.
+//
+// See https://developer.github.com/v3/repos/hooks/#create-a-hook.
+func (api *HooksAPI) Create(hook HookRequest) (*Hook, error) {
+	return api.CreateContext(context.Background(), hook)
+}
+
+// CreateContext is like Create but uses the provided context.
+func (api *HooksAPI) CreateContext(ctx context.Context, hook HookRequest) (*Hook, error) {
+	if hook.Name == "" {
+		hook.Name = "web"
+	}
+
+	b, err := json.Marshal(hook)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := api.httpPost(ctx, api.hooksURL(""), string(b))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response Hook
+
+	j := json.NewDecoder(resp.Body)
+	if err = j.Decode(&response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// Get gets a webhook by ID.
+func (api *HooksAPI) Get(hookID int64) (*Hook, error) {
+	return api.GetContext(context.Background(), hookID)
+}
+
+// GetContext is like Get but uses the provided context.
+func (api *HooksAPI) GetContext(ctx context.Context, hookID int64) (*Hook, error) {
+	resp, err := api.httpGet(ctx, api.hookURL(hookID, ""))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var hook Hook
+
+	j := json.NewDecoder(resp.Body)
+	if err = j.Decode(&hook); err != nil {
+		return nil, err
+	}
+
+	return &hook, nil
+}
+
+// List lists all webhooks.
+func (api *HooksAPI) List() ([]Hook, error) {
+	return api.ListContext(context.Background())
+}
+
+// ListContext is like List but uses the provided context.
+func (api *HooksAPI) ListContext(ctx context.Context) ([]Hook, error) {
+	var hooks []Hook
+	if err := api.ListPagesContext(ctx, nil).All(&hooks); err != nil {
+		return nil, err
+	}
+
+	return hooks, nil
+}
+
+// ListPages returns a PageIterator over the webhooks. Each page decodes to []Hook.
+func (api *HooksAPI) ListPages(opts *ListOptions) *PageIterator {
+	return api.ListPagesContext(context.Background(), opts)
+}
+
+// ListPagesContext is like ListPages but uses ctx for each page request.
+func (api *HooksAPI) ListPagesContext(ctx context.Context, opts *ListOptions) *PageIterator {
+	return api.NewPageIteratorContext(ctx, api.hooksURL(""), opts)
+}
+
+// Edit updates a webhook. Only the fields set in hook are changed.
+//
+// See https://developer.github.com/v3/repos/hooks/#edit-a-hook.
+func (api *HooksAPI) Edit(hookID int64, hook HookRequest) (*Hook, error) {
+	return api.EditContext(context.Background(), hookID, hook)
+}
+
+// EditContext is like Edit but uses the provided context.
+func (api *HooksAPI) EditContext(ctx context.Context, hookID int64, hook HookRequest) (*Hook, error) {
+	b, err := json.Marshal(hook)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := api.httpPatch(ctx, api.hookURL(hookID, ""), string(b))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response Hook
+
+	j := json.NewDecoder(resp.Body)
+	if err = j.Decode(&response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// Delete deletes a webhook.
+func (api *HooksAPI) Delete(hookID int64) error {
+	return api.DeleteContext(context.Background(), hookID)
+}
+
+// DeleteContext is like Delete but uses the provided context.
+func (api *HooksAPI) DeleteContext(ctx context.Context, hookID int64) error {
+	return api.sendNoContent(ctx, "DELETE", api.hookURL(hookID, ""))
+}
+
+// Ping sends a "ping" event to a webhook.
+//
+// See https://developer.github.com/v3/repos/hooks/#ping-a-hook.
+func (api *HooksAPI) Ping(hookID int64) error {
+	return api.PingContext(context.Background(), hookID)
+}
+
+// PingContext is like Ping but uses the provided context.
+func (api *HooksAPI) PingContext(ctx context.Context, hookID int64) error {
+	return api.sendNoContent(ctx, "POST", api.hookURL(hookID, "/pings"))
+}
+
+// Test triggers the webhook with the latest push to the repository. If the hook isn't subscribed to "push" events
+// nothing is sent. Organization hooks don't support Test.
+//
+// See https://developer.github.com/v3/repos/hooks/#test-a-push-hook.
+func (api *HooksAPI) Test(hookID int64) error {
+	return api.TestContext(context.Background(), hookID)
+}
+
+// TestContext is like Test but uses the provided context.
+func (api *HooksAPI) TestContext(ctx context.Context, hookID int64) error {
+	return api.sendNoContent(ctx, "POST", api.hookURL(hookID, "/tests"))
+}
+
+// sendNoContent sends a request without a body to an endpoint which returns "204 No Content".
+func (api *HooksAPI) sendNoContent(ctx context.Context, method, url string) error {
+	resp, err := api.doHTTPRequest(ctx, method, url, nil, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// EnsureHook makes sure a webhook with desired's payload URL exists and has desired's events, content type,
+// insecure_ssl setting and active state. The hook is created if it doesn't exist and edited if it differs; otherwise
+// nothing is changed, so EnsureHook can be called on every deploy. It returns the hook and whether it was created or
+// changed.
+//
+// GitHub doesn't return the secret, so an existing hook's secret is only compared by whether one is set. desired's
+// secret is sent whenever the hook is created or edited; to rotate the secret, call Edit.
+func (api *HooksAPI) EnsureHook(desired HookRequest) (*Hook, bool, error) {
+	return api.EnsureHookContext(context.Background(), desired)
+}
+
+// EnsureHookContext is like EnsureHook but uses the provided context.
+func (api *HooksAPI) EnsureHookContext(ctx context.Context, desired HookRequest) (*Hook, bool, error) {
+	if desired.Config == nil || desired.Config.URL == "" {
+		return nil, false, errors.New("EnsureHook: desired.Config.URL is required")
+	}
+
+	hooks, err := api.ListContext(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var hook *Hook
+	for i := range hooks {
+		if hooks[i].Config.URL != desired.Config.URL {
+			continue
+		}
+
+		if hookMatches(&hooks[i], desired) {
+			return &hooks[i], false, nil
+		}
+
+		if hook, err = api.EditContext(ctx, hooks[i].ID, desired); err != nil {
+			return nil, false, err
+		}
+		return hook, true, nil
+	}
+
+	if hook, err = api.CreateContext(ctx, desired); err != nil {
+		return nil, false, err
+	}
+	return hook, true, nil
+}
+
+// hookMatches returns true if existing has the desired configuration, applying GitHub's defaults to desired.
+func hookMatches(existing *Hook, desired HookRequest) bool {
+	active := desired.Active == nil || *desired.Active
+	if existing.Active != active {
+		return false
+	}
+
+	events := desired.Events
+	if len(events) == 0 {
+		events = []string{"push"}
+	}
+	if !sameStrings(existing.Events, events) {
+		return false
+	}
+
+	contentType := desired.Config.ContentType
+	if contentType == "" {
+		contentType = "form"
+	}
+	insecureSSL := desired.Config.InsecureSSL
+	if insecureSSL == "" {
+		insecureSSL = "0"
+	}
+	existingInsecureSSL := existing.Config.InsecureSSL
+	if existingInsecureSSL == "" {
+		existingInsecureSSL = "0"
+	}
+	if existing.Config.ContentType != contentType || existingInsecureSSL != insecureSSL {
+		return false
+	}
+
+	return (existing.Config.Secret != "") == (desired.Config.Secret != "")
+}
+
+// sameStrings returns true if a and b contain the same strings, ignoring order and duplicates.
+func sameStrings(a, b []string) bool {
+	a, b = uniqueSorted(a), uniqueSorted(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func uniqueSorted(s []string) []string {
+	set := make(map[string]bool, len(s))
+	var result []string
+	for _, v := range s {
+		if !set[v] {
+			set[v] = true
+			result = append(result, v)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// HookDelivery is an attempt by GitHub to deliver an event to a webhook. Request and Response are only set by
+// HooksAPI.GetDelivery.
+type HookDelivery struct {
+	ID             int64     `json:"id"`
+	GUID           string    `json:"guid"`
+	DeliveredAt    time.Time `json:"delivered_at"`
+	Redelivery     bool      `json:"redelivery"`
+	Duration       float64   `json:"duration"`
+	Status         string    `json:"status"`
+	StatusCode     int       `json:"status_code"`
+	Event          string    `json:"event"`
+	Action         *string   `json:"action"`
+	InstallationID *int64    `json:"installation_id"`
+	RepositoryID   *int64    `json:"repository_id"`
+	URL            string    `json:"url"`
+	Request        *struct {
+		Headers map[string]string `json:"headers"`
+		Payload json.RawMessage   `json:"payload"`
+	} `json:"request"`
+	Response *struct {
+		Headers map[string]string `json:"headers"`
+		Payload *string           `json:"payload"`
+	} `json:"response"`
+}
+
+// Failed returns true if the webhook endpoint didn't respond with a 2xx status code. A StatusCode of 0 means GitHub
+// couldn't connect.
+func (d *HookDelivery) Failed() bool {
+	return d.StatusCode < 200 || d.StatusCode >= 300
+}
+
+// ListDeliveries lists a webhook's deliveries, newest first, following pagination.
+func (api *HooksAPI) ListDeliveries(hookID int64) ([]HookDelivery, error) {
+	return api.ListDeliveriesContext(context.Background(), hookID)
+}
+
+// ListDeliveriesContext is like ListDeliveries but uses the provided context.
+func (api *HooksAPI) ListDeliveriesContext(ctx context.Context, hookID int64) ([]HookDelivery, error) {
+	var deliveries []HookDelivery
+	if err := api.ListDeliveriesPagesContext(ctx, hookID, nil).All(&deliveries); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// ListDeliveriesPages returns a PageIterator over a webhook's deliveries, newest first. Each page decodes to
+// []HookDelivery. GitHub keeps deliveries for 3 days.
+func (api *HooksAPI) ListDeliveriesPages(hookID int64, opts *ListOptions) *PageIterator {
+	return api.ListDeliveriesPagesContext(context.Background(), hookID, opts)
+}
+
+// ListDeliveriesPagesContext is like ListDeliveriesPages but uses ctx for each page request.
+func (api *HooksAPI) ListDeliveriesPagesContext(ctx context.Context, hookID int64, opts *ListOptions) *PageIterator {
+	return api.NewPageIteratorContext(ctx, api.hookURL(hookID, "/deliveries"), opts)
+}
+
+// GetDelivery gets a webhook delivery, including the request GitHub sent and the response it received.
+func (api *HooksAPI) GetDelivery(hookID int64, deliveryID int64) (*HookDelivery, error) {
+	return api.GetDeliveryContext(context.Background(), hookID, deliveryID)
+}
+
+// GetDeliveryContext is like GetDelivery but uses the provided context.
+func (api *HooksAPI) GetDeliveryContext(ctx context.Context, hookID int64, deliveryID int64) (*HookDelivery, error) {
+	resp, err := api.httpGet(ctx, api.hookURL(hookID, fmt.Sprintf("/deliveries/%d", deliveryID)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var delivery HookDelivery
+
+	j := json.NewDecoder(resp.Body)
+	if err = j.Decode(&delivery); err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// Redeliver asks GitHub to deliver a webhook delivery again. The redelivery has the same GUID and is sent to the
+// webhook's current URL, so it goes through the endpoint's usual validation.
+func (api *HooksAPI) Redeliver(hookID int64, deliveryID int64) error {
+	return api.RedeliverContext(context.Background(), hookID, deliveryID)
+}
+
+// RedeliverContext is like Redeliver but uses the provided context.
+func (api *HooksAPI) RedeliverContext(ctx context.Context, hookID int64, deliveryID int64) error {
+	return api.sendNoContent(ctx, "POST", api.hookURL(hookID, fmt.Sprintf("/deliveries/%d/attempts", deliveryID)))
+}
+
+// RedeliverFailed redelivers the webhook deliveries made between since and until which failed and haven't since
+// succeeded, even if the success came after until. Each GUID is redelivered once, using its latest attempt. A zero
+// until means now. It returns the deliveries which were redelivered.
+//
+// Redelivered events pass through the endpoint's signature validation as usual. If the endpoint uses a
+// DeliveryStore, failed deliveries must have been forgotten (WebhookServer does this when a callback fails) or the
+// redelivery is rejected as a duplicate.
+func (api *HooksAPI) RedeliverFailed(hookID int64, since, until time.Time) ([]HookDelivery, error) {
+	return api.RedeliverFailedContext(context.Background(), hookID, since, until)
+}
+
+// RedeliverFailedContext is like RedeliverFailed but uses the provided context.
+func (api *HooksAPI) RedeliverFailedContext(ctx context.Context, hookID int64, since, until time.Time) ([]HookDelivery, error) {
+	var failed []HookDelivery
+	handled := make(map[string]bool)
+
+	// deliveries are listed newest first, so the first attempt seen for a GUID is its latest
+	it := api.ListDeliveriesPagesContext(ctx, hookID, &ListOptions{PerPage: 100})
+	for it.Next() {
+		var page []HookDelivery
+		if err := it.Decode(&page); err != nil {
+			return nil, err
+		}
+
+		for _, delivery := range page {
+			if delivery.DeliveredAt.Before(since) {
+				it.Stop()
+				break
+			}
+			if !until.IsZero() && !delivery.DeliveredAt.Before(until) {
+				// a later success means earlier failures in the window don't need redelivering
+				if !delivery.Failed() {
+					handled[delivery.GUID] = true
+				}
+				continue
+			}
+			if handled[delivery.GUID] {
+				continue
+			}
+			handled[delivery.GUID] = true
+
+			if delivery.Failed() {
+				failed = append(failed, delivery)
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	var redelivered []HookDelivery
+	for _, delivery := range failed {
+		if err := api.RedeliverContext(ctx, hookID, delivery.ID); err != nil {
+			return redelivered, err
+		}
+		redelivered = append(redelivered, delivery)
+	}
+
+	return redelivered, nil
+}
@@ -0,0 +1,449 @@
+package ghapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeHooks is an in-memory hooks endpoint.
+type fakeHooks struct {
+	mtx      sync.Mutex
+	hooks    map[int64]*Hook
+	nextID   int64
+	requests []string
+}
+
+func makeHooksTestServer(t *testing.T, hooksPath string) (*httptest.Server, *fakeHooks) {
+	f := &fakeHooks{hooks: make(map[int64]*Hook), nextID: 1}
+
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mtx.Lock()
+		defer f.mtx.Unlock()
+
+		f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+
+		if r.URL.Path == hooksPath {
+			switch r.Method {
+			case "GET":
+				f.list(t, ts.URL, w, r)
+			case "POST":
+				var req HookRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatal(err)
+				}
+				hook := &Hook{ID: f.nextID, Active: true, Events: []string{"push"}}
+				f.nextID++
+				applyHookRequest(hook, req)
+				f.hooks[hook.ID] = hook
+				w.WriteHeader(201)
+				writeTestJSON(t, w, hook)
+			default:
+				w.WriteHeader(405)
+			}
+			return
+		}
+
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, hooksPath+"/"), "/")
+		id, err := strconv.ParseInt(parts[0], 10, 64)
+		hook, ok := f.hooks[id]
+		if err != nil || !ok {
+			w.WriteHeader(404)
+			return
+		}
+
+		switch {
+		case len(parts) == 2 && r.Method == "POST" && (parts[1] == "pings" || parts[1] == "tests"):
+			w.WriteHeader(204)
+		case len(parts) == 1 && r.Method == "GET":
+			writeTestJSON(t, w, hook)
+		case len(parts) == 1 && r.Method == "PATCH":
+			var req HookRequest
+			if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatal(err)
+			}
+			applyHookRequest(hook, req)
+			writeTestJSON(t, w, hook)
+		case len(parts) == 1 && r.Method == "DELETE":
+			delete(f.hooks, id)
+			w.WriteHeader(204)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	return ts, f
+}
+
+func (f *fakeHooks) list(t *testing.T, baseURL string, w http.ResponseWriter, r *http.Request) {
+	var ids []int64
+	for id := range f.hooks {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	perPage, page := 30, 1
+	var err error
+	if p := r.URL.Query().Get("per_page"); p != "" {
+		if perPage, err = strconv.Atoi(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if p := r.URL.Query().Get("page"); p != "" {
+		if page, err = strconv.Atoi(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hooks := []*Hook{}
+	for i := (page - 1) * perPage; i < len(ids) && i < page*perPage; i++ {
+		hooks = append(hooks, f.hooks[ids[i]])
+	}
+	if page*perPage < len(ids) {
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s?per_page=%d&page=%d>; rel="next"`, baseURL, r.URL.Path, perPage, page+1))
+	}
+	writeTestJSON(t, w, hooks)
+}
+
+func (f *fakeHooks) writes() []string {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	var writes []string
+	for _, req := range f.requests {
+		if !strings.HasPrefix(req, "GET ") {
+			writes = append(writes, req)
+		}
+	}
+	return writes
+}
+
+// applyHookRequest updates hook the way GitHub does, applying defaults and masking the secret.
+func applyHookRequest(hook *Hook, req HookRequest) {
+	if req.Name != "" {
+		hook.Name = req.Name
+	}
+	if req.Config != nil {
+		hook.Config = *req.Config
+		if hook.Config.Secret != "" {
+			hook.Config.Secret = "********"
+		}
+		if hook.Config.ContentType == "" {
+			hook.Config.ContentType = "form"
+		}
+		if hook.Config.InsecureSSL == "" {
+			hook.Config.InsecureSSL = "0"
+		}
+	}
+	if req.Events != nil {
+		hook.Events = req.Events
+	}
+	if req.Active != nil {
+		hook.Active = *req.Active
+	}
+}
+
+func writeTestJSON(t *testing.T, w http.ResponseWriter, v interface{}) {
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHooksAPI_Create(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "POST", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/hooks", r.URL.Path, "r.URL.Path")
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		expect(t, "web", body["name"], "body[\"name\"]")
+		config := body["config"].(map[string]interface{})
+		expect(t, "https://example.org/webhook", config["url"], "config[\"url\"]")
+		expect(t, "json", config["content_type"], "config[\"content_type\"]")
+		expect(t, "s3cr3t", config["secret"], "config[\"secret\"]")
+
+		w.WriteHeader(201)
+		if _, err := w.Write([]byte(`{"id":12,"name":"web","active":true,"events":["pull_request"],
+			"config":{"url":"https://example.org/webhook","content_type":"json","secret":"********","insecure_ssl":"0"}}`)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	defer ts.Close()
+
+	hook, err := api.Hooks.Create(HookRequest{
+		Config: &HookConfig{URL: "https://example.org/webhook", ContentType: "json", Secret: "s3cr3t"},
+		Events: []string{"pull_request"},
+	})
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, int64(12), hook.ID, "hook.ID")
+	expect(t, "********", hook.Config.Secret, "hook.Config.Secret")
+	expect(t, "pull_request", hook.Events[0], "hook.Events[0]")
+}
+
+func TestHooksAPI_OrganizationHooks(t *testing.T) {
+	ts, f := makeHooksTestServer(t, "/orgs/test_owner/hooks")
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken)
+	hook, err := api.OrganizationHooks.Create(HookRequest{Config: &HookConfig{URL: "https://example.org/webhook"}})
+	expectNil(t, err, "err")
+
+	expectNil(t, api.OrganizationHooks.Ping(hook.ID), "Ping")
+	expectNil(t, api.OrganizationHooks.Delete(hook.ID), "Delete")
+	_, err = api.OrganizationHooks.Get(hook.ID)
+	expect(t, true, IsNotFound(err), "IsNotFound(err)")
+
+	expected := []string{
+		"POST /orgs/test_owner/hooks",
+		"POST /orgs/test_owner/hooks/1/pings",
+		"DELETE /orgs/test_owner/hooks/1",
+	}
+	writes := f.writes()
+	expect(t, len(expected), len(writes), "len(writes)")
+	for i := range expected {
+		expect(t, expected[i], writes[i], "writes[i]")
+	}
+}
+
+func TestHooksAPI_ListPaginates(t *testing.T) {
+	ts, _ := makeHooksTestServer(t, "/repos/test_owner/test_repository/hooks")
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken)
+	for i := 0; i < 5; i++ {
+		_, err := api.Hooks.Create(HookRequest{Config: &HookConfig{URL: fmt.Sprintf("https://example.org/%d", i)}})
+		expectNil(t, err, "err")
+	}
+
+	it := api.Hooks.ListPages(&ListOptions{PerPage: 2})
+	var hooks []Hook
+	expectNil(t, it.All(&hooks), "All")
+	expect(t, 5, len(hooks), "len(hooks)")
+	expect(t, 3, it.Pages(), "it.Pages()")
+	expect(t, "https://example.org/4", hooks[4].Config.URL, "hooks[4].Config.URL")
+}
+
+func TestHooksAPI_EditAndTest(t *testing.T) {
+	ts, f := makeHooksTestServer(t, "/repos/test_owner/test_repository/hooks")
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken)
+	hook, err := api.Hooks.Create(HookRequest{Config: &HookConfig{URL: "https://example.org/webhook"}})
+	expectNil(t, err, "err")
+
+	inactive := false
+	hook, err = api.Hooks.Edit(hook.ID, HookRequest{Events: []string{"push", "status"}, Active: &inactive})
+	expectNil(t, err, "err")
+	expect(t, 2, len(hook.Events), "len(hook.Events)")
+	expect(t, false, hook.Active, "hook.Active")
+	expect(t, "https://example.org/webhook", hook.Config.URL, "hook.Config.URL")
+
+	expectNil(t, api.Hooks.Test(hook.ID), "Test")
+	writes := f.writes()
+	expect(t, "POST /repos/test_owner/test_repository/hooks/1/tests", writes[len(writes)-1], "last write")
+}
+
+func TestHooksAPI_EnsureHook(t *testing.T) {
+	ts, f := makeHooksTestServer(t, "/repos/test_owner/test_repository/hooks")
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken)
+
+	// an unrelated hook is left alone
+	_, err := api.Hooks.Create(HookRequest{Config: &HookConfig{URL: "https://ci.example.org/hook"}})
+	expectNil(t, err, "err")
+
+	desired := HookRequest{
+		Config: &HookConfig{URL: "https://example.org/webhook", ContentType: "json", Secret: "s3cr3t"},
+		Events: []string{"pull_request", "issue_comment"},
+	}
+
+	hook, changed, err := api.Hooks.EnsureHook(desired)
+	expectNil(t, err, "err")
+	expect(t, true, changed, "changed after create")
+	expect(t, int64(2), hook.ID, "hook.ID")
+
+	// same events in a different order
+	desired.Events = []string{"issue_comment", "pull_request"}
+	_, changed, err = api.Hooks.EnsureHook(desired)
+	expectNil(t, err, "err")
+	expect(t, false, changed, "changed when up to date")
+
+	desired.Events = append(desired.Events, "push")
+	hook, changed, err = api.Hooks.EnsureHook(desired)
+	expectNil(t, err, "err")
+	expect(t, true, changed, "changed after edit")
+	expect(t, int64(2), hook.ID, "hook.ID")
+	expect(t, 3, len(hook.Events), "len(hook.Events)")
+
+	expected := []string{
+		"POST /repos/test_owner/test_repository/hooks",
+		"POST /repos/test_owner/test_repository/hooks",
+		"PATCH /repos/test_owner/test_repository/hooks/2",
+	}
+	writes := f.writes()
+	expect(t, len(expected), len(writes), "len(writes)")
+	for i := range expected {
+		expect(t, expected[i], writes[i], "writes[i]")
+	}
+}
+
+func TestHooksAPI_EnsureHookRequiresURL(t *testing.T) {
+	api := makeGitHubAPI()
+	_, _, err := api.Hooks.EnsureHook(HookRequest{Events: []string{"push"}})
+	expectNotNil(t, err, "err")
+}
+
+func TestHooksAPI_GetDelivery(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "GET", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/hooks/12/deliveries/345", r.URL.Path, "r.URL.Path")
+
+		if _, err := w.Write([]byte(`{"id":345,"guid":"0b989ba4-242f-11e5-81e1-c7b6966d2516","delivered_at":"2019-06-03T00:57:16Z",
+			"redelivery":false,"duration":0.27,"status":"OK","status_code":200,"event":"issues","action":"opened",
+			"installation_id":null,"repository_id":17273051,"url":"https://example.org/webhook",
+			"request":{"headers":{"X-GitHub-Event":"issues"},"payload":{"action":"opened"}},
+			"response":{"headers":{"Content-Type":"text/plain"},"payload":"ok"}}`)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	defer ts.Close()
+
+	delivery, err := api.Hooks.GetDelivery(12, 345)
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, int64(345), delivery.ID, "delivery.ID")
+	expect(t, "0b989ba4-242f-11e5-81e1-c7b6966d2516", delivery.GUID, "delivery.GUID")
+	expect(t, false, delivery.Failed(), "delivery.Failed()")
+	expect(t, "opened", *delivery.Action, "delivery.Action")
+	expect(t, true, delivery.InstallationID == nil, "delivery.InstallationID == nil")
+	expect(t, "issues", delivery.Request.Headers["X-GitHub-Event"], "delivery.Request.Headers")
+	expect(t, `{"action":"opened"}`, string(delivery.Request.Payload), "delivery.Request.Payload")
+	expect(t, "ok", *delivery.Response.Payload, "delivery.Response.Payload")
+}
+
+// makeDeliveriesTestServer returns a server which lists deliveries for hook 12, two per page, and accepts
+// redeliveries, and a function returning the requests it received.
+func makeDeliveriesTestServer(t *testing.T, deliveries []HookDelivery) (*httptest.Server, func() []string) {
+	var mtx sync.Mutex
+	var requests []string
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+
+		const path = "/repos/test_owner/test_repository/hooks/12/deliveries"
+		switch {
+		case r.Method == "GET" && r.URL.Path == path:
+			page := 1
+			if p := r.URL.Query().Get("page"); p != "" {
+				var err error
+				if page, err = strconv.Atoi(p); err != nil {
+					t.Fatal(err)
+				}
+			}
+			end := page * 2
+			if end < len(deliveries) {
+				w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=%d>; rel="next"`, ts.URL, path, page+1))
+			} else {
+				end = len(deliveries)
+			}
+			writeTestJSON(t, w, deliveries[(page-1)*2:end])
+		case r.Method == "POST" && strings.HasPrefix(r.URL.Path, path+"/") && strings.HasSuffix(r.URL.Path, "/attempts"):
+			w.WriteHeader(202)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+
+	return ts, func() []string {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return append([]string(nil), requests...)
+	}
+}
+
+func TestHooksAPI_RedeliverFailed(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2019, 6, 3, hour, 0, 0, 0, time.UTC) }
+
+	// newest first, two per page
+	deliveries := []HookDelivery{
+		{ID: 7, GUID: "e", StatusCode: 500, DeliveredAt: at(13)},
+		{ID: 6, GUID: "c", StatusCode: 500, DeliveredAt: at(12)},
+		{ID: 5, GUID: "a", StatusCode: 200, DeliveredAt: at(11)},
+		{ID: 4, GUID: "b", StatusCode: 0, DeliveredAt: at(10)},
+		{ID: 3, GUID: "a", StatusCode: 502, DeliveredAt: at(9)},
+		{ID: 2, GUID: "d", StatusCode: 500, DeliveredAt: at(7)},
+		{ID: 1, GUID: "f", StatusCode: 500, DeliveredAt: at(6)},
+	}
+
+	ts, requests := makeDeliveriesTestServer(t, deliveries)
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken)
+	redelivered, err := api.Hooks.RedeliverFailed(12, at(8), at(13))
+	expectNil(t, err, "err")
+
+	// "e" is outside the window, "a" succeeded on redelivery and "d" is too old
+	expect(t, 2, len(redelivered), "len(redelivered)")
+	expect(t, "c", redelivered[0].GUID, "redelivered[0].GUID")
+	expect(t, "b", redelivered[1].GUID, "redelivered[1].GUID")
+
+	expected := []string{
+		"GET /repos/test_owner/test_repository/hooks/12/deliveries?per_page=100",
+		"GET /repos/test_owner/test_repository/hooks/12/deliveries?page=2",
+		"GET /repos/test_owner/test_repository/hooks/12/deliveries?page=3",
+		"POST /repos/test_owner/test_repository/hooks/12/deliveries/6/attempts",
+		"POST /repos/test_owner/test_repository/hooks/12/deliveries/4/attempts",
+	}
+	actual := requests()
+	expect(t, len(expected), len(actual), "len(requests)")
+	for i := range expected {
+		expect(t, expected[i], actual[i], "requests[i]")
+	}
+}
+
+func TestHooksAPI_RedeliverFailedSkipsLaterSuccess(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2019, 6, 3, hour, 0, 0, 0, time.UTC) }
+
+	// "c" failed inside the window but succeeded after until; "e" failed after until
+	deliveries := []HookDelivery{
+		{ID: 9, GUID: "c", StatusCode: 200, DeliveredAt: at(14)},
+		{ID: 8, GUID: "e", StatusCode: 500, DeliveredAt: at(13)},
+		{ID: 7, GUID: "c", StatusCode: 500, DeliveredAt: at(12)},
+		{ID: 6, GUID: "e", StatusCode: 500, DeliveredAt: at(11)},
+		{ID: 5, GUID: "b", StatusCode: 502, DeliveredAt: at(10)},
+	}
+
+	ts, requests := makeDeliveriesTestServer(t, deliveries)
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken)
+	redelivered, err := api.Hooks.RedeliverFailed(12, at(8), at(13))
+	expectNil(t, err, "err")
+
+	expect(t, 2, len(redelivered), "len(redelivered)")
+	expect(t, "e", redelivered[0].GUID, "redelivered[0].GUID")
+	expect(t, int64(6), redelivered[0].ID, "redelivered[0].ID")
+	expect(t, "b", redelivered[1].GUID, "redelivered[1].GUID")
+
+	for _, request := range requests() {
+		if strings.HasSuffix(request, "/deliveries/7/attempts") {
+			t.Errorf("%s: %q succeeded after until and should not be redelivered", request, "c")
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package ghapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// mockingbirdPreviewAcceptHeader is required by the Timeline API while it's in preview.
+const mockingbirdPreviewAcceptHeader = "application/vnd.github.mockingbird-preview+json"
+
+// IssueEvent is an event on an issue or pull request, for example "labeled", "assigned", "closed", "merged",
+// "referenced" or "renamed". The fields set depend on Event.
+// See https://developer.github.com/v3/issues/events/#events-1.
+type IssueEvent struct {
+	ID    int    `json:"id"`
+	URL   string `json:"url"`
+	Actor *User  `json:"actor"`
+	Event string `json:"event"`
+	// CommitID is the SHA of the commit which referenced, closed or merged the issue.
+	CommitID  *string   `json:"commit_id"`
+	CommitURL *string   `json:"commit_url"`
+	CreatedAt time.Time `json:"created_at"`
+	// Label is set for "labeled" and "unlabeled"; only Name and Color are returned.
+	Label *IssueLabel `json:"label"`
+	// Assignee and Assigner are set for "assigned" and "unassigned".
+	Assignee *User `json:"assignee"`
+	Assigner *User `json:"assigner"`
+	// ReviewRequester and RequestedReviewer are set for "review_requested" and "review_request_removed".
+	ReviewRequester   *User `json:"review_requester"`
+	RequestedReviewer *User `json:"requested_reviewer"`
+	// Milestone is set for "milestoned" and "demilestoned"; only Title is returned.
+	Milestone *Milestone `json:"milestone"`
+	// Rename is set for "renamed".
+	Rename *struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	} `json:"rename"`
+	// LockReason is set for "locked".
+	LockReason *string `json:"lock_reason"`
+	// Issue is only set by ListRepositoryIssueEvents.
+	Issue *IssueResponse `json:"issue"`
+}
+
+// TimelineComment is a "commented" timeline event.
+type TimelineComment struct {
+	IssueCommentResponse
+	Event string `json:"event"`
+	Actor *User  `json:"actor"`
+}
+
+// TimelineCrossReference is a "cross-referenced" timeline event, created when the issue is mentioned in another
+// issue or pull request.
+type TimelineCrossReference struct {
+	Event     string    `json:"event"`
+	Actor     *User     `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Source    struct {
+		// Type is "issue".
+		Type  string         `json:"type"`
+		Issue *IssueResponse `json:"issue"`
+	} `json:"source"`
+}
+
+// TimelineCommit is a "committed" timeline event, for a commit pushed to a pull request's branch.
+type TimelineCommit struct {
+	Event   string `json:"event"`
+	SHA     string `json:"sha"`
+	URL     string `json:"url"`
+	HTMLURL string `json:"html_url"`
+	Author  struct {
+		Name  string    `json:"name"`
+		Email string    `json:"email"`
+		Date  time.Time `json:"date"`
+	} `json:"author"`
+	Committer struct {
+		Name  string    `json:"name"`
+		Email string    `json:"email"`
+		Date  time.Time `json:"date"`
+	} `json:"committer"`
+	Message string `json:"message"`
+}
+
+// TimelineReview is a "reviewed" timeline event on a pull request.
+type TimelineReview struct {
+	Event       string    `json:"event"`
+	ID          int       `json:"id"`
+	User        User      `json:"user"`
+	Body        string    `json:"body"`
+	State       string    `json:"state"`
+	HTMLURL     string    `json:"html_url"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// TimelineItem is an entry in an issue's timeline. Item is decoded according to Event:
+//
+//	"commented"        *TimelineComment
+//	"cross-referenced" *TimelineCrossReference
+//	"committed"        *TimelineCommit
+//	"reviewed"         *TimelineReview
+//	anything else      *IssueEvent
+type TimelineItem struct {
+	Event string
+	Item  interface{}
+}
+
+// timelineItems creates the Item for each Event which isn't decoded to *IssueEvent.
+var timelineItems = map[string]func() interface{}{
+	"commented":        func() interface{} { return &TimelineComment{} },
+	"cross-referenced": func() interface{} { return &TimelineCrossReference{} },
+	"committed":        func() interface{} { return &TimelineCommit{} },
+	"reviewed":         func() interface{} { return &TimelineReview{} },
+}
+
+// UnmarshalJSON decodes b into the type for its "event" field.
+func (t *TimelineItem) UnmarshalJSON(b []byte) error {
+	var header struct {
+		Event string `json:"event"`
+	}
+	if err := json.Unmarshal(b, &header); err != nil {
+		return err
+	}
+
+	item := interface{}(&IssueEvent{})
+	if newItem, ok := timelineItems[header.Event]; ok {
+		item = newItem()
+	}
+	if err := json.Unmarshal(b, item); err != nil {
+		return fmt.Errorf("timeline event %q: %w", header.Event, err)
+	}
+
+	t.Event = header.Event
+	t.Item = item
+	return nil
+}
+
+// MarshalJSON encodes Item.
+func (t TimelineItem) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Item)
+}
+
+// Time returns when the event happened: the commit's author date for "committed" and the submission time for
+// "reviewed".
+func (t *TimelineItem) Time() time.Time {
+	switch item := t.Item.(type) {
+	case *TimelineComment:
+		return item.CreatedAt
+	case *TimelineCrossReference:
+		return item.CreatedAt
+	case *TimelineCommit:
+		return item.Author.Date
+	case *TimelineReview:
+		return item.SubmittedAt
+	case *IssueEvent:
+		return item.CreatedAt
+	}
+	return time.Time{}
+}
+
+// Login returns the login of the user who caused the event, or "" for "committed" events and events by deleted
+// users.
+func (t *TimelineItem) Login() string {
+	var user *User
+	switch item := t.Item.(type) {
+	case *TimelineComment:
+		user = &item.User
+	case *TimelineCrossReference:
+		user = item.Actor
+	case *TimelineReview:
+		user = &item.User
+	case *IssueEvent:
+		user = item.Actor
+	}
+	if user == nil {
+		return ""
+	}
+	return user.Login
+}
+
+// Timeline is an issue's timeline, oldest first.
+type Timeline []TimelineItem
+
+// FirstResponse returns the first comment or review by a user other than author, usually the issue's creator, or
+// nil if there isn't one. Use it with IssueResponse.CreatedAt to compute time-to-first-response.
+func (tl Timeline) FirstResponse(author string) *TimelineItem {
+	for i := range tl {
+		if tl[i].Event != "commented" && tl[i].Event != "reviewed" {
+			continue
+		}
+		if login := tl[i].Login(); login != "" && login != author {
+			return &tl[i]
+		}
+	}
+	return nil
+}
+
+// LastClosed returns the last "closed" event, or nil if the issue was never closed. An issue which was reopened
+// still has a "closed" event; check IssueResponse.State.
+func (tl Timeline) LastClosed() *TimelineItem {
+	for i := len(tl) - 1; i >= 0; i-- {
+		if tl[i].Event == "closed" {
+			return &tl[i]
+		}
+	}
+	return nil
+}
+
+// ListIssueEvents lists the events on an issue or pull request, oldest first.
+func (api *IssueAPI) ListIssueEvents(issueNumber int) ([]IssueEvent, error) {
+	return api.ListIssueEventsContext(context.Background(), issueNumber)
+}
+
+// ListIssueEventsContext is like ListIssueEvents but uses the provided context.
+func (api *IssueAPI) ListIssueEventsContext(ctx context.Context, issueNumber int) ([]IssueEvent, error) {
+	var allEvents []IssueEvent
+	if err := api.ListIssueEventsPagesContext(ctx, issueNumber, nil).All(&allEvents); err != nil {
+		return nil, err
+	}
+
+	return allEvents, nil
+}
+
+// ListIssueEventsPages returns a PageIterator over the events on an issue or pull request. Each page decodes to
+// []IssueEvent.
+func (api *IssueAPI) ListIssueEventsPages(issueNumber int, opts *ListOptions) *PageIterator {
+	return api.ListIssueEventsPagesContext(context.Background(), issueNumber, opts)
+}
+
+// ListIssueEventsPagesContext is like ListIssueEventsPages but uses ctx for each page request.
+func (api *IssueAPI) ListIssueEventsPagesContext(ctx context.Context, issueNumber int, opts *ListOptions) *PageIterator {
+	url := api.getURL(fmt.Sprintf("/repos/:owner/:repo/issues/%d/events", issueNumber))
+	return api.NewPageIteratorContext(ctx, url, opts)
+}
+
+// ListRepositoryIssueEvents lists the events on all of the repository's issues and pull requests, newest first. Each
+// event's Issue is set.
+func (api *IssueAPI) ListRepositoryIssueEvents() ([]IssueEvent, error) {
+	return api.ListRepositoryIssueEventsContext(context.Background())
+}
+
+// ListRepositoryIssueEventsContext is like ListRepositoryIssueEvents but uses the provided context.
+func (api *IssueAPI) ListRepositoryIssueEventsContext(ctx context.Context) ([]IssueEvent, error) {
+	var allEvents []IssueEvent
+	if err := api.ListRepositoryIssueEventsPagesContext(ctx, nil).All(&allEvents); err != nil {
+		return nil, err
+	}
+
+	return allEvents, nil
+}
+
+// ListRepositoryIssueEventsPages returns a PageIterator over the events on all of the repository's issues and pull
+// requests. Each page decodes to []IssueEvent.
+func (api *IssueAPI) ListRepositoryIssueEventsPages(opts *ListOptions) *PageIterator {
+	return api.ListRepositoryIssueEventsPagesContext(context.Background(), opts)
+}
+
+// ListRepositoryIssueEventsPagesContext is like ListRepositoryIssueEventsPages but uses ctx for each page request.
+func (api *IssueAPI) ListRepositoryIssueEventsPagesContext(ctx context.Context, opts *ListOptions) *PageIterator {
+	url := api.getURL("/repos/:owner/:repo/issues/events")
+	return api.NewPageIteratorContext(ctx, url, opts)
+}
+
+// ListIssueTimeline lists the timeline of an issue or pull request, oldest first. The timeline includes comments,
+// commits, reviews and cross-references as well as the events returned by ListIssueEvents.
+func (api *IssueAPI) ListIssueTimeline(issueNumber int) (Timeline, error) {
+	return api.ListIssueTimelineContext(context.Background(), issueNumber)
+}
+
+// ListIssueTimelineContext is like ListIssueTimeline but uses the provided context.
+func (api *IssueAPI) ListIssueTimelineContext(ctx context.Context, issueNumber int) (Timeline, error) {
+	var timeline Timeline
+	if err := api.ListIssueTimelinePagesContext(ctx, issueNumber, nil).All(&timeline); err != nil {
+		return nil, err
+	}
+
+	return timeline, nil
+}
+
+// ListIssueTimelinePages returns a PageIterator over the timeline of an issue or pull request. Each page decodes to
+// []TimelineItem.
+func (api *IssueAPI) ListIssueTimelinePages(issueNumber int, opts *ListOptions) *PageIterator {
+	return api.ListIssueTimelinePagesContext(context.Background(), issueNumber, opts)
+}
+
+// ListIssueTimelinePagesContext is like ListIssueTimelinePages but uses ctx for each page request.
+func (api *IssueAPI) ListIssueTimelinePagesContext(ctx context.Context, issueNumber int, opts *ListOptions) *PageIterator {
+	url := api.getURL(fmt.Sprintf("/repos/:owner/:repo/issues/%d/timeline", issueNumber))
+	it := api.NewPageIteratorContext(ctx, url, opts)
+	it.accept = mockingbirdPreviewAcceptHeader
+	return it
+}
@@ -0,0 +1,107 @@
+package ghapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+const issue1347TimelineResponse = `[
+  {"id":1,"event":"labeled","actor":{"login":"octocat"},"created_at":"2011-04-22T13:40:00Z","label":{"name":"bug","color":"f29513"}},
+  {"id":2,"event":"commented","user":{"login":"octocat"},"actor":{"login":"octocat"},"body":"More details","created_at":"2011-04-22T13:45:00Z"},
+  {"event":"cross-referenced","actor":{"login":"hubot"},"created_at":"2011-04-22T14:00:00Z","source":{"type":"issue","issue":{"number":1348,"title":"Fix the bug"}}},
+  {"event":"committed","sha":"7638417db6d59f3c431d3e1f261cc637155684cd","message":"Fix the bug","author":{"name":"Monalisa Octocat","email":"support@github.com","date":"2011-04-22T14:30:00Z"}},
+  {"id":3,"event":"reviewed","user":{"login":"hubot"},"state":"approved","submitted_at":"2011-04-22T15:00:00Z"},
+  {"id":4,"event":"renamed","actor":{"login":"octocat"},"created_at":"2011-04-22T15:10:00Z","rename":{"from":"Bug","to":"Found a bug"}},
+  {"id":5,"event":"closed","actor":{"login":"hubot"},"commit_id":"7638417db6d59f3c431d3e1f261cc637155684cd","created_at":"2011-04-22T16:00:00Z"}
+]`
+
+func TestIssueApi_ListIssueTimeline(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "GET", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/issues/1347/timeline", r.URL.Path, "r.URL.Path")
+		expect(t, mockingbirdPreviewAcceptHeader, r.Header.Get("Accept"), "Accept")
+
+		_, err := w.Write([]byte(issue1347TimelineResponse))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	timeline, err := api.Issue.ListIssueTimeline(1347)
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, 7, len(timeline), "len(timeline)")
+
+	labeled := timeline[0].Item.(*IssueEvent)
+	expect(t, "bug", labeled.Label.Name, "labeled.Label.Name")
+
+	comment := timeline[1].Item.(*TimelineComment)
+	expect(t, "More details", comment.Body, "comment.Body")
+
+	crossReference := timeline[2].Item.(*TimelineCrossReference)
+	expect(t, 1348, crossReference.Source.Issue.Number, "crossReference.Source.Issue.Number")
+
+	commit := timeline[3].Item.(*TimelineCommit)
+	expect(t, "Fix the bug", commit.Message, "commit.Message")
+	expect(t, date("2011-04-22T14:30:00Z"), timeline[3].Time(), "timeline[3].Time()")
+	expect(t, "", timeline[3].Login(), "timeline[3].Login()")
+
+	review := timeline[4].Item.(*TimelineReview)
+	expect(t, "approved", review.State, "review.State")
+
+	renamed := timeline[5].Item.(*IssueEvent)
+	expect(t, "Found a bug", renamed.Rename.To, "renamed.Rename.To")
+
+	// octocat opened the issue, so hubot's review is the first response
+	created := date("2011-04-22T13:33:48Z")
+	first := timeline.FirstResponse("octocat")
+	expectNotNil(t, first, "first")
+	expect(t, "reviewed", first.Event, "first.Event")
+	expect(t, 86*time.Minute+12*time.Second, first.Time().Sub(created), "time to first response")
+
+	closed := timeline.LastClosed()
+	expectNotNil(t, closed, "closed")
+	expect(t, "hubot", closed.Login(), "closed.Login()")
+	expect(t, date("2011-04-22T16:00:00Z"), closed.Time(), "closed.Time()")
+}
+
+func TestIssueApi_ListIssueEvents(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "GET", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/issues/1347/events", r.URL.Path, "r.URL.Path")
+
+		_, err := w.Write([]byte(`[{"id":1,"event":"assigned","actor":{"login":"octocat"},"assignee":{"login":"hubot"},
+			"assigner":{"login":"octocat"},"created_at":"2011-04-22T13:40:00Z"}]`))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	events, err := api.Issue.ListIssueEvents(1347)
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, 1, len(events), "len(events)")
+	expect(t, "assigned", events[0].Event, "events[0].Event")
+	expect(t, "hubot", events[0].Assignee.Login, "events[0].Assignee.Login")
+	expectNil(t, events[0].Issue, "events[0].Issue")
+}
+
+func TestIssueApi_ListRepositoryIssueEvents(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "GET", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/issues/events", r.URL.Path, "r.URL.Path")
+
+		_, err := w.Write([]byte(`[{"id":1,"event":"closed","actor":{"login":"octocat"},"created_at":"2011-04-22T16:00:00Z",
+			"issue":{"number":1347,"title":"Found a bug"}}]`))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	events, err := api.Issue.ListRepositoryIssueEvents()
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, 1, len(events), "len(events)")
+	expect(t, 1347, events[0].Issue.Number, "events[0].Issue.Number")
+}
@@ -1,60 +1,50 @@
 package ghapi
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // IssueLabel contains Issue label information. This type is used in IssueResponse.
 type IssueLabel struct {
-	ID      int    `json:"id"`
-	URL     string `json:"url"`
-	Name    string `json:"name"`
-	Color   string `json:"color"`
-	Default bool   `json:"default"`
+	ID          int    `json:"id"`
+	URL         string `json:"url"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+	Default     bool   `json:"default"`
 }
 
 // IssueResponse contains Issue information. This value is returned by IssuesAPI for get and edit API calls.
 type IssueResponse struct {
-	ID            int          `json:"id"`
-	URL           string       `json:"url"`
-	RepositoryURL string       `json:"repository_url"`
-	LabelsURL     string       `json:"labels_url"`
-	CommentsURL   string       `json:"comments_url"`
-	EventsURL     string       `json:"events_url"`
-	HTMLURL       string       `json:"html_url"`
-	Number        int          `json:"number"`
-	State         string       `json:"state"`
-	Title         string       `json:"title"`
-	Body          string       `json:"body"`
-	User          User         `json:"user"`
-	Labels        []IssueLabel `json:"labels"`
-	Assignee      *User        `json:"assignee"`
-	Milestone     struct {
-		URL          string     `json:"url"`
-		HTMLURL      string     `json:"html_url"`
-		LabelsURL    string     `json:"labels_url"`
-		ID           int        `json:"id"`
-		Number       int        `json:"number"`
-		State        string     `json:"state"`
-		Title        string     `json:"title"`
-		Description  string     `json:"description"`
-		Creator      User       `json:"creator"`
-		OpenIssues   int        `json:"open_issues"`
-		ClosedIssues int        `json:"closed_issues"`
-		CreatedAt    time.Time  `json:"created_at"`
-		UpdatedAt    time.Time  `json:"updated_at"`
-		ClosedAt     *time.Time `json:"closed_at"`
-		DueOn        *time.Time `json:"due_on"`
-	} `json:"milestone"`
-	Locked      bool `json:"locked"`
-	Comments    int  `json:"comments"`
-	PullRequest struct {
+	ID               int          `json:"id"`
+	URL              string       `json:"url"`
+	RepositoryURL    string       `json:"repository_url"`
+	LabelsURL        string       `json:"labels_url"`
+	CommentsURL      string       `json:"comments_url"`
+	EventsURL        string       `json:"events_url"`
+	HTMLURL          string       `json:"html_url"`
+	Number           int          `json:"number"`
+	State            string       `json:"state"`
+	Title            string       `json:"title"`
+	Body             string       `json:"body"`
+	User             User         `json:"user"`
+	Labels           []IssueLabel `json:"labels"`
+	Assignee         *User        `json:"assignee"`
+	Assignees        []User       `json:"assignees"`
+	Milestone        Milestone    `json:"milestone"`
+	Locked           bool         `json:"locked"`
+	ActiveLockReason *string      `json:"active_lock_reason"`
+	Comments         int          `json:"comments"`
+	PullRequest      struct {
 		URL      string `json:"url"`
 		HTMLURL  string `json:"html_url"`
 		DiffURL  string `json:"diff_url"`
@@ -66,11 +56,114 @@ type IssueResponse struct {
 	ClosedBy  *User      `json:"closed_by"`
 }
 
+// IssueRequest contains the fields of an issue to create or edit. Nil fields are left unchanged when editing; Title is
+// required when creating.
+type IssueRequest struct {
+	Title *string `json:"title,omitempty"`
+	Body  *string `json:"body,omitempty"`
+	// State is "open" or "closed". It's ignored when creating an issue.
+	State *string `json:"state,omitempty"`
+	// Milestone is the number of the milestone to associate the issue with. A pointer to 0 removes the milestone.
+	Milestone *int      `json:"-"`
+	Labels    *[]string `json:"labels,omitempty"`
+	Assignees *[]string `json:"assignees,omitempty"`
+}
+
+// MarshalJSON encodes the request, sending a null milestone when Milestone points to 0.
+func (r IssueRequest) MarshalJSON() ([]byte, error) {
+	type issueRequest IssueRequest
+	body := struct {
+		issueRequest
+		Milestone interface{} `json:"milestone,omitempty"`
+	}{issueRequest: issueRequest(r)}
+
+	if r.Milestone != nil {
+		if *r.Milestone == 0 {
+			body.Milestone = json.RawMessage("null")
+		} else {
+			body.Milestone = *r.Milestone
+		}
+	}
+
+	return json.Marshal(body)
+}
+
+// IssueListOptions filters the issues returned by ListIssues. Zero values use GitHub's defaults, which list open
+// issues sorted by creation date, newest first. Pull requests are included in the results; check
+// IssueResponse.PullRequest.URL to tell them apart.
+type IssueListOptions struct {
+	// Milestone is a milestone number, "*" for issues with any milestone, or "none" for issues without one.
+	Milestone string
+	// State is "open", "closed", or "all".
+	State string
+	// Assignee is a login, "*" for assigned issues, or "none" for unassigned issues.
+	Assignee string
+	// Creator is the login of the user who created the issue.
+	Creator string
+	// Mentioned is the login of a user mentioned in the issue.
+	Mentioned string
+	// Labels are label names; only issues with all of them are returned.
+	Labels []string
+	// Sort is "created", "updated", or "comments".
+	Sort string
+	// Direction is "asc" or "desc".
+	Direction string
+	// Since only returns issues updated at or after this time.
+	Since time.Time
+}
+
+func (o *IssueListOptions) query() string {
+	if o == nil {
+		return ""
+	}
+
+	q := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	set("milestone", o.Milestone)
+	set("state", o.State)
+	set("assignee", o.Assignee)
+	set("creator", o.Creator)
+	set("mentioned", o.Mentioned)
+	set("labels", strings.Join(o.Labels, ","))
+	set("sort", o.Sort)
+	set("direction", o.Direction)
+	if !o.Since.IsZero() {
+		q.Set("since", o.Since.UTC().Format(time.RFC3339))
+	}
+
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
+
+// sailorVPreviewAcceptHeader is required to send a lock reason while it's in preview.
+const sailorVPreviewAcceptHeader = "application/vnd.github.sailor-v-preview+json"
+
+// LockReason is the reason given for locking an issue's conversation.
+type LockReason string
+
+const (
+	// LockReasonOffTopic is "off-topic".
+	LockReasonOffTopic LockReason = "off-topic"
+	// LockReasonTooHeated is "too heated".
+	LockReasonTooHeated LockReason = "too heated"
+	// LockReasonResolved is "resolved".
+	LockReasonResolved LockReason = "resolved"
+	// LockReasonSpam is "spam".
+	LockReasonSpam LockReason = "spam"
+)
+
 // IssueCommentResponse returns information about a specific comment on an issue.
 type IssueCommentResponse struct {
 	ID        int       `json:"id"`
 	URL       string    `json:"url"`
 	HTMLURL   string    `json:"html_url"`
+	IssueURL  string    `json:"issue_url"`
 	Body      string    `json:"body"`
 	User      User      `json:"user"`
 	CreatedAt time.Time `json:"created_at"`
@@ -79,13 +172,23 @@ type IssueCommentResponse struct {
 
 // DeleteIssueComment deletes an issue comment by ID.
 func (api *IssueAPI) DeleteIssueComment(commentID int) error {
+	return api.DeleteIssueCommentContext(context.Background(), commentID)
+}
+
+// DeleteIssueCommentContext is like DeleteIssueComment but uses the provided context.
+func (api *IssueAPI) DeleteIssueCommentContext(ctx context.Context, commentID int) error {
 	url := api.getURL("/repos/:owner/:repo/issues/comments/" + strconv.Itoa(commentID))
-	return api.DeleteIssueCommentByURL(url)
+	return api.DeleteIssueCommentByURLContext(ctx, url)
 }
 
 // DeleteIssueCommentByURL deletes an issue comment by URL.
 func (api *IssueAPI) DeleteIssueCommentByURL(url string) error {
-	resp, err := api.httpDelete(url)
+	return api.DeleteIssueCommentByURLContext(context.Background(), url)
+}
+
+// DeleteIssueCommentByURLContext is like DeleteIssueCommentByURL but uses the provided context.
+func (api *IssueAPI) DeleteIssueCommentByURLContext(ctx context.Context, url string) error {
+	resp, err := api.httpDelete(ctx, url)
 	if err != nil {
 		return err
 	}
@@ -96,9 +199,61 @@ func (api *IssueAPI) DeleteIssueCommentByURL(url string) error {
 
 // GetIssueComment gets an issue comment by ID.
 func (api *IssueAPI) GetIssueComment(commentID int) (*IssueCommentResponse, error) {
+	return api.GetIssueCommentContext(context.Background(), commentID)
+}
+
+// GetIssueCommentContext is like GetIssueComment but uses the provided context.
+func (api *IssueAPI) GetIssueCommentContext(ctx context.Context, commentID int) (*IssueCommentResponse, error) {
 	url := api.getURL("/repos/:owner/:repo/issues/comments/" + strconv.Itoa(commentID))
 
-	resp, err := api.httpGet(url)
+	resp, err := api.httpGet(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var issueComment IssueCommentResponse
+
+	j := json.NewDecoder(resp.Body)
+	if err = j.Decode(&issueComment); err != nil {
+		return nil, err
+	}
+
+	return &issueComment, nil
+}
+
+// CreateIssueComment adds a comment to an issue or pull request.
+func (api *IssueAPI) CreateIssueComment(issueNumber int, body string) (*IssueCommentResponse, error) {
+	return api.CreateIssueCommentContext(context.Background(), issueNumber, body)
+}
+
+// CreateIssueCommentContext is like CreateIssueComment but uses the provided context.
+func (api *IssueAPI) CreateIssueCommentContext(ctx context.Context, issueNumber int, body string) (*IssueCommentResponse, error) {
+	url := api.getURL(fmt.Sprintf("/repos/:owner/:repo/issues/%d/comments", issueNumber))
+	return api.sendIssueComment(ctx, "POST", url, body)
+}
+
+// EditIssueComment replaces the body of an issue comment.
+func (api *IssueAPI) EditIssueComment(commentID int, body string) (*IssueCommentResponse, error) {
+	return api.EditIssueCommentContext(context.Background(), commentID, body)
+}
+
+// EditIssueCommentContext is like EditIssueComment but uses the provided context.
+func (api *IssueAPI) EditIssueCommentContext(ctx context.Context, commentID int, body string) (*IssueCommentResponse, error) {
+	url := api.getURL("/repos/:owner/:repo/issues/comments/" + strconv.Itoa(commentID))
+	return api.sendIssueComment(ctx, "PATCH", url, body)
+}
+
+func (api *IssueAPI) sendIssueComment(ctx context.Context, method, url, body string) (*IssueCommentResponse, error) {
+	b, err := json.Marshal(struct {
+		Body string `json:"body"`
+	}{body})
+	if err != nil {
+		return nil, err
+	}
+	reqBody := string(b)
+
+	resp, err := api.doHTTPRequest(ctx, method, url, &reqBody, "")
 	if err != nil {
 		return nil, err
 	}
@@ -114,15 +269,83 @@ func (api *IssueAPI) GetIssueComment(commentID int) (*IssueCommentResponse, erro
 	return &issueComment, nil
 }
 
+// ListIssueComments lists the comments on an issue or pull request, oldest first.
+func (api *IssueAPI) ListIssueComments(issueNumber int) ([]IssueCommentResponse, error) {
+	return api.ListIssueCommentsContext(context.Background(), issueNumber)
+}
+
+// ListIssueCommentsContext is like ListIssueComments but uses the provided context.
+func (api *IssueAPI) ListIssueCommentsContext(ctx context.Context, issueNumber int) ([]IssueCommentResponse, error) {
+	var allComments []IssueCommentResponse
+	if err := api.ListIssueCommentsPagesContext(ctx, issueNumber, nil).All(&allComments); err != nil {
+		return nil, err
+	}
+
+	return allComments, nil
+}
+
+// ListIssueCommentsPages returns a PageIterator over the comments on an issue or pull request. Each page decodes to
+// []IssueCommentResponse.
+func (api *IssueAPI) ListIssueCommentsPages(issueNumber int, opts *ListOptions) *PageIterator {
+	return api.ListIssueCommentsPagesContext(context.Background(), issueNumber, opts)
+}
+
+// ListIssueCommentsPagesContext is like ListIssueCommentsPages but uses ctx for each page request.
+func (api *IssueAPI) ListIssueCommentsPagesContext(ctx context.Context, issueNumber int, opts *ListOptions) *PageIterator {
+	url := api.getURL(fmt.Sprintf("/repos/:owner/:repo/issues/%d/comments", issueNumber))
+	return api.NewPageIteratorContext(ctx, url, opts)
+}
+
+// ListRepositoryIssueComments lists the comments on all of the repository's issues and pull requests, oldest first.
+// If since is not the zero time only comments updated at or after since are returned.
+func (api *IssueAPI) ListRepositoryIssueComments(since time.Time) ([]IssueCommentResponse, error) {
+	return api.ListRepositoryIssueCommentsContext(context.Background(), since)
+}
+
+// ListRepositoryIssueCommentsContext is like ListRepositoryIssueComments but uses the provided context.
+func (api *IssueAPI) ListRepositoryIssueCommentsContext(ctx context.Context, since time.Time) ([]IssueCommentResponse, error) {
+	var allComments []IssueCommentResponse
+	if err := api.ListRepositoryIssueCommentsPagesContext(ctx, since, nil).All(&allComments); err != nil {
+		return nil, err
+	}
+
+	return allComments, nil
+}
+
+// ListRepositoryIssueCommentsPages returns a PageIterator over the comments on all of the repository's issues and
+// pull requests. Each page decodes to []IssueCommentResponse.
+func (api *IssueAPI) ListRepositoryIssueCommentsPages(since time.Time, opts *ListOptions) *PageIterator {
+	return api.ListRepositoryIssueCommentsPagesContext(context.Background(), since, opts)
+}
+
+// ListRepositoryIssueCommentsPagesContext is like ListRepositoryIssueCommentsPages but uses ctx for each page request.
+func (api *IssueAPI) ListRepositoryIssueCommentsPagesContext(ctx context.Context, since time.Time, opts *ListOptions) *PageIterator {
+	path := "/repos/:owner/:repo/issues/comments"
+	if !since.IsZero() {
+		path += "?since=" + url.QueryEscape(since.UTC().Format(time.RFC3339))
+	}
+	return api.NewPageIteratorContext(ctx, api.getURL(path), opts)
+}
+
 // GetIssue gets an issue by issue number.
 func (api *IssueAPI) GetIssue(issueNumber int) (*IssueResponse, error) {
+	return api.GetIssueContext(context.Background(), issueNumber)
+}
+
+// GetIssueContext is like GetIssue but uses the provided context.
+func (api *IssueAPI) GetIssueContext(ctx context.Context, issueNumber int) (*IssueResponse, error) {
 	url := api.getURL("/repos/:owner/:repo/issues/" + strconv.Itoa(issueNumber))
-	return api.GetIssueByURL(url)
+	return api.GetIssueByURLContext(ctx, url)
 }
 
 // GetIssueByURL gets an issue by URL.
 func (api *IssueAPI) GetIssueByURL(url string) (*IssueResponse, error) {
-	resp, err := api.httpGet(url)
+	return api.GetIssueByURLContext(context.Background(), url)
+}
+
+// GetIssueByURLContext is like GetIssueByURL but uses the provided context.
+func (api *IssueAPI) GetIssueByURLContext(ctx context.Context, url string) (*IssueResponse, error) {
+	resp, err := api.httpGet(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -138,41 +361,145 @@ func (api *IssueAPI) GetIssueByURL(url string) (*IssueResponse, error) {
 	return &issue, nil
 }
 
-// UpdateIssueAssignee updates an issue's assignee by issue number.
+// UpdateIssueAssignee updates an issue's assignee by issue number. The assignee replaces all of the issue's
+// assignees; see AddAssignees, RemoveAssignees and ReplaceAssignees to manage multiple assignees.
 func (api *IssueAPI) UpdateIssueAssignee(issueNumber int, assignee string) (*IssueResponse, error) {
+	return api.UpdateIssueAssigneeContext(context.Background(), issueNumber, assignee)
+}
+
+// UpdateIssueAssigneeContext is like UpdateIssueAssignee but uses the provided context.
+func (api *IssueAPI) UpdateIssueAssigneeContext(ctx context.Context, issueNumber int, assignee string) (*IssueResponse, error) {
 	url := api.getURL("/repos/:owner/:repo/issues/" + strconv.Itoa(issueNumber))
-	return api.UpdateIssueAssigneeByURL(url, assignee)
+	return api.UpdateIssueAssigneeByURLContext(ctx, url, assignee)
 }
 
 // UpdateIssueAssigneeByURL updates an issue's assignee by issue URL.
 func (api *IssueAPI) UpdateIssueAssigneeByURL(url, assignee string) (*IssueResponse, error) {
-	// TODO (judwhite): there can be multiple assignees
+	return api.UpdateIssueAssigneeByURLContext(context.Background(), url, assignee)
+}
+
+// UpdateIssueAssigneeByURLContext is like UpdateIssueAssigneeByURL but uses the provided context.
+func (api *IssueAPI) UpdateIssueAssigneeByURLContext(ctx context.Context, url, assignee string) (*IssueResponse, error) {
 	body := struct {
 		Assignee string `json:"assignee"`
 	}{assignee}
 
-	return api.updateIssueByURL(url, body)
+	return api.updateIssueByURL(ctx, url, body)
 }
 
 // UpdateIssueLabels updates an issue's labels by issue number. The labels passed become the new
 // labels. See AddLabel and RemoveLabel to add/remove individual labels.
 func (api *IssueAPI) UpdateIssueLabels(issueNumber int, labels []string) (*IssueResponse, error) {
+	return api.UpdateIssueLabelsContext(context.Background(), issueNumber, labels)
+}
+
+// UpdateIssueLabelsContext is like UpdateIssueLabels but uses the provided context.
+func (api *IssueAPI) UpdateIssueLabelsContext(ctx context.Context, issueNumber int, labels []string) (*IssueResponse, error) {
 	url := api.getURL("/repos/:owner/:repo/issues/" + strconv.Itoa(issueNumber))
-	return api.UpdateIssueLabelsByURL(url, labels)
+	return api.UpdateIssueLabelsByURLContext(ctx, url, labels)
 }
 
 // UpdateIssueLabelsByURL updates an issue's labels by issue URL. The labels passed become the new
 // labels. See AddLabel and RemoveLabel to add/remove individual labels.
 func (api *IssueAPI) UpdateIssueLabelsByURL(url string, labels []string) (*IssueResponse, error) {
+	return api.UpdateIssueLabelsByURLContext(context.Background(), url, labels)
+}
+
+// UpdateIssueLabelsByURLContext is like UpdateIssueLabelsByURL but uses the provided context.
+func (api *IssueAPI) UpdateIssueLabelsByURLContext(ctx context.Context, url string, labels []string) (*IssueResponse, error) {
 	body := struct {
 		Labels []string `json:"labels"`
 	}{labels}
 
-	return api.updateIssueByURL(url, body)
+	return api.updateIssueByURL(ctx, url, body)
+}
+
+// AddAssignees adds users to an issue's assignees. Users who can't be assigned to issues in the repository are
+// silently ignored by GitHub; see CheckAssignee.
+func (api *IssueAPI) AddAssignees(issueNumber int, logins []string) (*IssueResponse, error) {
+	return api.AddAssigneesContext(context.Background(), issueNumber, logins)
+}
+
+// AddAssigneesContext is like AddAssignees but uses the provided context.
+func (api *IssueAPI) AddAssigneesContext(ctx context.Context, issueNumber int, logins []string) (*IssueResponse, error) {
+	return api.sendAssignees(ctx, "POST", issueNumber, logins)
+}
+
+// RemoveAssignees removes users from an issue's assignees.
+func (api *IssueAPI) RemoveAssignees(issueNumber int, logins []string) (*IssueResponse, error) {
+	return api.RemoveAssigneesContext(context.Background(), issueNumber, logins)
+}
+
+// RemoveAssigneesContext is like RemoveAssignees but uses the provided context.
+func (api *IssueAPI) RemoveAssigneesContext(ctx context.Context, issueNumber int, logins []string) (*IssueResponse, error) {
+	return api.sendAssignees(ctx, "DELETE", issueNumber, logins)
+}
+
+// ReplaceAssignees sets an issue's assignees to logins. An empty logins removes all assignees.
+func (api *IssueAPI) ReplaceAssignees(issueNumber int, logins []string) (*IssueResponse, error) {
+	return api.ReplaceAssigneesContext(context.Background(), issueNumber, logins)
+}
+
+// ReplaceAssigneesContext is like ReplaceAssignees but uses the provided context.
+func (api *IssueAPI) ReplaceAssigneesContext(ctx context.Context, issueNumber int, logins []string) (*IssueResponse, error) {
+	if logins == nil {
+		logins = []string{}
+	}
+	return api.EditIssueContext(ctx, issueNumber, IssueRequest{Assignees: &logins})
+}
+
+// CheckAssignee returns true if login can be assigned to issues in the repository.
+func (api *IssueAPI) CheckAssignee(login string) (bool, error) {
+	return api.CheckAssigneeContext(context.Background(), login)
+}
+
+// CheckAssigneeContext is like CheckAssignee but uses the provided context.
+func (api *IssueAPI) CheckAssigneeContext(ctx context.Context, login string) (bool, error) {
+	resp, err := api.httpGet(ctx, api.getURL("/repos/:owner/:repo/assignees/"+url.PathEscape(login)))
+	if err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	return true, nil
+}
+
+func (api *IssueAPI) sendAssignees(ctx context.Context, method string, issueNumber int, logins []string) (*IssueResponse, error) {
+	b, err := json.Marshal(struct {
+		Assignees []string `json:"assignees"`
+	}{logins})
+	if err != nil {
+		return nil, err
+	}
+	body := string(b)
+
+	url := api.getURL(fmt.Sprintf("/repos/:owner/:repo/issues/%d/assignees", issueNumber))
+	resp, err := api.doHTTPRequest(ctx, method, url, &body, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var issue IssueResponse
+
+	j := json.NewDecoder(resp.Body)
+	if err = j.Decode(&issue); err != nil {
+		return nil, err
+	}
+
+	return &issue, nil
 }
 
 // AddLabel adds a label to an issue.
 func (api *IssueAPI) AddLabel(issueNumber int, labelName string) error {
+	return api.AddLabelContext(context.Background(), issueNumber, labelName)
+}
+
+// AddLabelContext is like AddLabel but uses the provided context.
+func (api *IssueAPI) AddLabelContext(ctx context.Context, issueNumber int, labelName string) error {
 	url := api.getURL(fmt.Sprintf("/repos/:owner/:repo/issues/%d/labels", issueNumber))
 
 	labels := []string{labelName}
@@ -180,7 +507,7 @@ func (api *IssueAPI) AddLabel(issueNumber int, labelName string) error {
 	if err != nil {
 		return err
 	}
-	resp, err := api.httpPost(url, string(b))
+	resp, err := api.httpPost(ctx, url, string(b))
 	if err != nil {
 		return err
 	}
@@ -192,10 +519,15 @@ func (api *IssueAPI) AddLabel(issueNumber int, labelName string) error {
 
 // RemoveLabel remove a label from an issue.
 func (api *IssueAPI) RemoveLabel(issueNumber int, labelName string) error {
+	return api.RemoveLabelContext(context.Background(), issueNumber, labelName)
+}
+
+// RemoveLabelContext is like RemoveLabel but uses the provided context.
+func (api *IssueAPI) RemoveLabelContext(ctx context.Context, issueNumber int, labelName string) error {
 	removeLabelURL := api.getURL(fmt.Sprintf(
 		"/repos/:owner/:repo/issues/%d/labels/", issueNumber) + url.PathEscape(labelName))
 
-	resp, err := api.httpDelete(removeLabelURL)
+	resp, err := api.httpDelete(ctx, removeLabelURL)
 	if err != nil {
 		return err
 	}
@@ -205,13 +537,153 @@ func (api *IssueAPI) RemoveLabel(issueNumber int, labelName string) error {
 	return err
 }
 
-func (api *IssueAPI) updateIssueByURL(url string, body interface{}) (*IssueResponse, error) {
+// CreateIssue creates an issue. issue.Title is required.
+func (api *IssueAPI) CreateIssue(issue IssueRequest) (*IssueResponse, error) {
+	return api.CreateIssueContext(context.Background(), issue)
+}
+
+// CreateIssueContext is like CreateIssue but uses the provided context.
+func (api *IssueAPI) CreateIssueContext(ctx context.Context, issue IssueRequest) (*IssueResponse, error) {
+	if issue.Title == nil || *issue.Title == "" {
+		return nil, errors.New("CreateIssue: issue.Title is required")
+	}
+
+	b, err := json.Marshal(issue)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := api.httpPost(ctx, api.getURL("/repos/:owner/:repo/issues"), string(b))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var created IssueResponse
+
+	j := json.NewDecoder(resp.Body)
+	if err = j.Decode(&created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// ListIssues lists the repository's issues matching filter. A nil filter lists open issues.
+func (api *IssueAPI) ListIssues(filter *IssueListOptions) ([]IssueResponse, error) {
+	return api.ListIssuesContext(context.Background(), filter)
+}
+
+// ListIssuesContext is like ListIssues but uses the provided context.
+func (api *IssueAPI) ListIssuesContext(ctx context.Context, filter *IssueListOptions) ([]IssueResponse, error) {
+	var allIssues []IssueResponse
+	if err := api.ListIssuesPagesContext(ctx, filter, nil).All(&allIssues); err != nil {
+		return nil, err
+	}
+
+	return allIssues, nil
+}
+
+// ListIssuesPages returns a PageIterator over the repository's issues matching filter. Each page decodes to
+// []IssueResponse.
+func (api *IssueAPI) ListIssuesPages(filter *IssueListOptions, opts *ListOptions) *PageIterator {
+	return api.ListIssuesPagesContext(context.Background(), filter, opts)
+}
+
+// ListIssuesPagesContext is like ListIssuesPages but uses ctx for each page request.
+func (api *IssueAPI) ListIssuesPagesContext(ctx context.Context, filter *IssueListOptions, opts *ListOptions) *PageIterator {
+	url := api.getURL("/repos/:owner/:repo/issues" + filter.query())
+	return api.NewPageIteratorContext(ctx, url, opts)
+}
+
+// EditIssue updates an issue by issue number. Only the non-nil fields of issue are changed.
+func (api *IssueAPI) EditIssue(issueNumber int, issue IssueRequest) (*IssueResponse, error) {
+	return api.EditIssueContext(context.Background(), issueNumber, issue)
+}
+
+// EditIssueContext is like EditIssue but uses the provided context.
+func (api *IssueAPI) EditIssueContext(ctx context.Context, issueNumber int, issue IssueRequest) (*IssueResponse, error) {
+	url := api.getURL("/repos/:owner/:repo/issues/" + strconv.Itoa(issueNumber))
+	return api.updateIssueByURL(ctx, url, issue)
+}
+
+// CloseIssue closes an issue.
+func (api *IssueAPI) CloseIssue(issueNumber int) (*IssueResponse, error) {
+	return api.CloseIssueContext(context.Background(), issueNumber)
+}
+
+// CloseIssueContext is like CloseIssue but uses the provided context.
+func (api *IssueAPI) CloseIssueContext(ctx context.Context, issueNumber int) (*IssueResponse, error) {
+	state := "closed"
+	return api.EditIssueContext(ctx, issueNumber, IssueRequest{State: &state})
+}
+
+// ReopenIssue reopens a closed issue.
+func (api *IssueAPI) ReopenIssue(issueNumber int) (*IssueResponse, error) {
+	return api.ReopenIssueContext(context.Background(), issueNumber)
+}
+
+// ReopenIssueContext is like ReopenIssue but uses the provided context.
+func (api *IssueAPI) ReopenIssueContext(ctx context.Context, issueNumber int) (*IssueResponse, error) {
+	state := "open"
+	return api.EditIssueContext(ctx, issueNumber, IssueRequest{State: &state})
+}
+
+// LockIssue locks an issue's conversation so only collaborators can comment. reason is optional; pass "" to lock
+// without one.
+func (api *IssueAPI) LockIssue(issueNumber int, reason LockReason) error {
+	return api.LockIssueContext(context.Background(), issueNumber, reason)
+}
+
+// LockIssueContext is like LockIssue but uses the provided context.
+func (api *IssueAPI) LockIssueContext(ctx context.Context, issueNumber int, reason LockReason) error {
+	url := api.getURL(fmt.Sprintf("/repos/:owner/:repo/issues/%d/lock", issueNumber))
+
+	body := "{}"
+	if reason != "" {
+		b, err := json.Marshal(struct {
+			LockReason LockReason `json:"lock_reason"`
+		}{reason})
+		if err != nil {
+			return err
+		}
+		body = string(b)
+	}
+
+	resp, err := api.doHTTPRequest(ctx, "PUT", url, &body, sailorVPreviewAcceptHeader)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// UnlockIssue unlocks an issue's conversation.
+func (api *IssueAPI) UnlockIssue(issueNumber int) error {
+	return api.UnlockIssueContext(context.Background(), issueNumber)
+}
+
+// UnlockIssueContext is like UnlockIssue but uses the provided context.
+func (api *IssueAPI) UnlockIssueContext(ctx context.Context, issueNumber int) error {
+	url := api.getURL(fmt.Sprintf("/repos/:owner/:repo/issues/%d/lock", issueNumber))
+
+	resp, err := api.httpDelete(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+func (api *IssueAPI) updateIssueByURL(ctx context.Context, url string, body interface{}) (*IssueResponse, error) {
 	b, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := api.httpPatch(url, string(b))
+	resp, err := api.httpPatch(ctx, url, string(b))
 	if err != nil {
 		return nil, err
 	}
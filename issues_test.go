@@ -1,9 +1,12 @@
 package ghapi
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -443,7 +446,7 @@ func TestIssueApi_updateIssueByUrl_ReturnsErrOnJsonMarshalErr(t *testing.T) {
 		Assignee func(string) `json:"assignee"`
 	}{nil}
 
-	issue, err := api.Issue.updateIssueByURL("http://error.org", data)
+	issue, err := api.Issue.updateIssueByURL(context.Background(), "http://error.org", data)
 
 	expectNil(t, issue, "issue")
 	if err != nil {
@@ -556,3 +559,334 @@ func TestIssueApi_GetIssueComment_ReturnsErrOnJsonDecodeErr(t *testing.T) {
 	expectNil(t, issueComment, "issueComment")
 	expectJSONSyntaxError(t, err, "invalid character 'j' looking for beginning of value")
 }
+
+func TestIssueApi_CreateIssue(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+
+		expectNil(t, err, "err")
+		expect(t, "POST", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/issues", r.URL.Path, "r.URL.Path")
+		expect(t, `{"title":"Found a bug","body":"I'm having a problem with this.","labels":["bug"],"milestone":1}`, string(b), "r.Body")
+
+		w.WriteHeader(201)
+		_, err = w.Write([]byte(getIssue1347Response))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	title, body, milestone := "Found a bug", "I'm having a problem with this.", 1
+	labels := []string{"bug"}
+	issue, err := api.Issue.CreateIssue(IssueRequest{Title: &title, Body: &body, Labels: &labels, Milestone: &milestone})
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expectIssue1347(t, issue)
+}
+
+func TestIssueApi_CreateIssue_RequiresTitle(t *testing.T) {
+	api := makeGitHubAPI()
+	_, err := api.Issue.CreateIssue(IssueRequest{})
+	expectNotNil(t, err, "err")
+}
+
+func TestIssueApi_ListIssues(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "GET", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/issues", r.URL.Path, "r.URL.Path")
+
+		q := r.URL.Query()
+		expect(t, "all", q.Get("state"), "state")
+		expect(t, "bug,ui", q.Get("labels"), "labels")
+		expect(t, "none", q.Get("assignee"), "assignee")
+		expect(t, "octocat", q.Get("creator"), "creator")
+		expect(t, "hubot", q.Get("mentioned"), "mentioned")
+		expect(t, "2011-04-22T13:33:48Z", q.Get("since"), "since")
+		expect(t, "updated", q.Get("sort"), "sort")
+		expect(t, "asc", q.Get("direction"), "direction")
+		expect(t, "", q.Get("milestone"), "milestone")
+
+		_, err := w.Write([]byte("[" + getIssue1347Response + "]"))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	issues, err := api.Issue.ListIssues(&IssueListOptions{
+		State:     "all",
+		Labels:    []string{"bug", "ui"},
+		Assignee:  "none",
+		Creator:   "octocat",
+		Mentioned: "hubot",
+		Since:     date("2011-04-22T13:33:48Z"),
+		Sort:      "updated",
+		Direction: "asc",
+	})
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, 1, len(issues), "len(issues)")
+	expectIssue1347(t, &issues[0])
+}
+
+func TestIssueApi_ListIssuesPages(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "closed", r.URL.Query().Get("state"), "state")
+		expect(t, "1", r.URL.Query().Get("per_page"), "per_page")
+
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?state=closed&per_page=1&page=2>; rel="next"`, ts.URL, r.URL.Path))
+		}
+		_, err := w.Write([]byte("[" + getIssue1347Response + "]"))
+		expectNil(t, err, "err")
+	}))
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken)
+	it := api.Issue.ListIssuesPages(&IssueListOptions{State: "closed"}, &ListOptions{PerPage: 1})
+
+	var issues []IssueResponse
+	expectNil(t, it.All(&issues), "All")
+	expect(t, 2, len(issues), "len(issues)")
+	expect(t, 2, it.Pages(), "it.Pages()")
+}
+
+func TestIssueApi_EditIssue(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+
+		expectNil(t, err, "err")
+		expect(t, "PATCH", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/issues/1347", r.URL.Path, "r.URL.Path")
+		expect(t, `{"title":"Found a bug","milestone":null}`, string(b), "r.Body")
+
+		_, err = w.Write([]byte(getIssue1347Response))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	title, noMilestone := "Found a bug", 0
+	issue, err := api.Issue.EditIssue(1347, IssueRequest{Title: &title, Milestone: &noMilestone})
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expectIssue1347(t, issue)
+}
+
+func TestIssueApi_CloseIssue(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+
+		expectNil(t, err, "err")
+		expect(t, "PATCH", r.Method, "r.Method")
+		expect(t, `{"state":"closed"}`, string(b), "r.Body")
+
+		_, err = w.Write([]byte(getIssue1347Response))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	_, err := api.Issue.CloseIssue(1347)
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+}
+
+func TestIssueApi_LockIssue(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+
+		expectNil(t, err, "err")
+		expect(t, "PUT", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/issues/1347/lock", r.URL.Path, "r.URL.Path")
+		expect(t, sailorVPreviewAcceptHeader, r.Header.Get("Accept"), "Accept")
+		expect(t, `{"lock_reason":"too heated"}`, string(b), "r.Body")
+
+		w.WriteHeader(204)
+	})
+	defer ts.Close()
+
+	err := api.Issue.LockIssue(1347, LockReasonTooHeated)
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+}
+
+func TestIssueApi_UnlockIssue(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "DELETE", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/issues/1347/lock", r.URL.Path, "r.URL.Path")
+
+		w.WriteHeader(204)
+	})
+	defer ts.Close()
+
+	err := api.Issue.UnlockIssue(1347)
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+}
+
+func TestIssueApi_AssigneesMethods(t *testing.T) {
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		call   func(api *GitHubAPI) (*IssueResponse, error)
+	}{
+		{
+			name:   "AddAssignees",
+			method: "POST",
+			path:   "/repos/test_owner/test_repository/issues/1347/assignees",
+			body:   `{"assignees":["octocat","hubot"]}`,
+			call: func(api *GitHubAPI) (*IssueResponse, error) {
+				return api.Issue.AddAssignees(1347, []string{"octocat", "hubot"})
+			},
+		},
+		{
+			name:   "RemoveAssignees",
+			method: "DELETE",
+			path:   "/repos/test_owner/test_repository/issues/1347/assignees",
+			body:   `{"assignees":["other"]}`,
+			call: func(api *GitHubAPI) (*IssueResponse, error) {
+				return api.Issue.RemoveAssignees(1347, []string{"other"})
+			},
+		},
+		{
+			name:   "ReplaceAssignees",
+			method: "PATCH",
+			path:   "/repos/test_owner/test_repository/issues/1347",
+			body:   `{"assignees":[]}`,
+			call: func(api *GitHubAPI) (*IssueResponse, error) {
+				return api.Issue.ReplaceAssignees(1347, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+				b, err := ioutil.ReadAll(r.Body)
+
+				expectNil(t, err, "err")
+				expect(t, tc.method, r.Method, "r.Method")
+				expect(t, tc.path, r.URL.Path, "r.URL.Path")
+				expect(t, tc.body, string(b), "r.Body")
+
+				_, err = w.Write([]byte(`{"number":1347,"assignee":{"login":"octocat"},"assignees":[{"login":"octocat"},{"login":"hubot"}]}`))
+				expectNil(t, err, "err")
+			})
+			defer ts.Close()
+
+			issue, err := tc.call(&api)
+			waitSignal(t, signal)
+
+			expectNil(t, err, "err")
+			expect(t, 2, len(issue.Assignees), "len(issue.Assignees)")
+			expect(t, "hubot", issue.Assignees[1].Login, "issue.Assignees[1].Login")
+		})
+	}
+}
+
+func TestIssueApi_CheckAssignee(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "GET", r.Method, "r.Method")
+		if r.URL.Path == "/repos/test_owner/test_repository/assignees/octocat" {
+			w.WriteHeader(204)
+		} else {
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ok, err := api.Issue.CheckAssignee("octocat")
+	waitSignal(t, signal)
+	expectNil(t, err, "err")
+	expect(t, true, ok, "octocat")
+
+	ok, err = api.Issue.CheckAssignee("hubot")
+	waitSignal(t, signal)
+	expectNil(t, err, "err")
+	expect(t, false, ok, "hubot")
+}
+
+func TestIssueApi_CreateIssueComment(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+
+		expectNil(t, err, "err")
+		expect(t, "POST", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/issues/1347/comments", r.URL.Path, "r.URL.Path")
+		expect(t, `{"body":"Me too"}`, string(b), "r.Body")
+
+		w.WriteHeader(201)
+		_, err = w.Write([]byte(getIssueComment1Response))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	comment, err := api.Issue.CreateIssueComment(1347, "Me too")
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, 1, comment.ID, "comment.ID")
+	expect(t, "Me too", comment.Body, "comment.Body")
+}
+
+func TestIssueApi_EditIssueComment(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+
+		expectNil(t, err, "err")
+		expect(t, "PATCH", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/issues/comments/1", r.URL.Path, "r.URL.Path")
+		expect(t, `{"body":"Me too"}`, string(b), "r.Body")
+
+		_, err = w.Write([]byte(getIssueComment1Response))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	comment, err := api.Issue.EditIssueComment(1, "Me too")
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, "octocat", comment.User.Login, "comment.User.Login")
+}
+
+func TestIssueApi_ListIssueComments(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "GET", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/issues/1347/comments", r.URL.Path, "r.URL.Path")
+
+		_, err := w.Write([]byte("[" + getIssueComment1Response + "]"))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	comments, err := api.Issue.ListIssueComments(1347)
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, 1, len(comments), "len(comments)")
+	expect(t, date("2011-04-14T16:00:49Z"), comments[0].CreatedAt, "comments[0].CreatedAt")
+}
+
+func TestIssueApi_ListRepositoryIssueComments(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "GET", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/issues/comments", r.URL.Path, "r.URL.Path")
+		expect(t, "2011-04-14T16:00:00Z", r.URL.Query().Get("since"), "since")
+
+		_, err := w.Write([]byte("[" + getIssueComment1Response + "]"))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	comments, err := api.Issue.ListRepositoryIssueComments(date("2011-04-14T16:00:00Z"))
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, 1, len(comments), "len(comments)")
+}
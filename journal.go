@@ -0,0 +1,184 @@
+package ghapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// JournalEntry is a delivery recorded in a Journal. Each entry is written as one line of JSON.
+type JournalEntry struct {
+	ReceivedAt time.Time       `json:"received_at"`
+	DeliveryID string          `json:"delivery_id,omitempty"`
+	HookID     int64           `json:"hook_id,omitempty"`
+	EventType  GitHubEventType `json:"event_type"`
+	Header     http.Header     `json:"header,omitempty"`
+	// Body is the payload exactly as received, so it still matches the signature in Header. It's encoded as base64.
+	Body []byte `json:"body"`
+}
+
+// Delivery returns the entry as a Delivery.
+func (e *JournalEntry) Delivery() *Delivery {
+	return &Delivery{
+		ID:        e.DeliveryID,
+		HookID:    e.HookID,
+		EventType: e.EventType,
+		Header:    e.Header,
+		Body:      e.Body,
+	}
+}
+
+// Journal appends validated deliveries to a local file, one JSON object per line, so they can be replayed with
+// ReplayJournal after a handler bug is fixed.
+//
+// A Journal is safe for concurrent use.
+type Journal struct {
+	now func() time.Time
+
+	mtx sync.Mutex
+	f   *os.File
+}
+
+// OpenJournal opens the journal file at path for appending, creating it if it doesn't exist.
+func OpenJournal(path string) (*Journal, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &Journal{f: f, now: time.Now}, nil
+}
+
+// Record appends delivery to the journal. The body must be JSON.
+func (j *Journal) Record(delivery *Delivery) error {
+	if !json.Valid(delivery.Body) {
+		return fmt.Errorf("delivery %s body is not valid JSON", delivery.ID)
+	}
+
+	b, err := json.Marshal(JournalEntry{
+		ReceivedAt: j.now().UTC(),
+		DeliveryID: delivery.ID,
+		HookID:     delivery.HookID,
+		EventType:  delivery.EventType,
+		Header:     delivery.Header,
+		Body:       delivery.Body,
+	})
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+
+	// a single write so concurrent processes appending to the same file don't interleave lines
+	_, err = j.f.Write(b)
+	return err
+}
+
+// Close closes the journal file.
+func (j *Journal) Close() error {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+
+	return j.f.Close()
+}
+
+// ReplayFilter selects the journal entries to replay. Zero values match everything.
+type ReplayFilter struct {
+	// EventTypes are the event types to replay.
+	EventTypes []GitHubEventType
+	// Repositories are the full names of the repositories to replay events for, for example "octocat/Hello-World".
+	Repositories []string
+	// Since excludes entries received before this time.
+	Since time.Time
+	// Until excludes entries received at or after this time.
+	Until time.Time
+}
+
+// Match returns true if entry passes the filter.
+func (f ReplayFilter) Match(entry *JournalEntry) bool {
+	if !f.Since.IsZero() && entry.ReceivedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !entry.ReceivedAt.Before(f.Until) {
+		return false
+	}
+
+	if len(f.EventTypes) != 0 {
+		found := false
+		for _, eventType := range f.EventTypes {
+			if eventType == entry.EventType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(f.Repositories) != 0 {
+		repo, _ := payloadOwner(entry.Body)
+		found := false
+		for _, r := range f.Repositories {
+			if r == repo {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ReplayJournal reads the journal file at path and calls handler, in order, with each delivery matching filter. Pass
+// WebhookServer.HandleDelivery to run the entries through the server's callbacks again. It returns the number of
+// deliveries replayed, and stops at the first error returned by handler.
+//
+// An incomplete last line, left by a process which stopped mid-write, is ignored.
+func ReplayJournal(ctx context.Context, path string, filter ReplayFilter, handler func(ctx context.Context, delivery *Delivery) error) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	replayed := 0
+	for lineNumber := 1; ; lineNumber++ {
+		if err = ctx.Err(); err != nil {
+			return replayed, err
+		}
+
+		var line []byte
+		line, err = r.ReadBytes('\n')
+		if err == io.EOF {
+			return replayed, nil
+		}
+		if err != nil {
+			return replayed, err
+		}
+
+		var entry JournalEntry
+		if err = json.Unmarshal(line, &entry); err != nil {
+			return replayed, fmt.Errorf("%s line %d: %v", path, lineNumber, err)
+		}
+		if !filter.Match(&entry) {
+			continue
+		}
+
+		if err = handler(ctx, entry.Delivery()); err != nil {
+			return replayed, fmt.Errorf("replaying delivery %s: %w", entry.DeliveryID, err)
+		}
+		replayed++
+	}
+}
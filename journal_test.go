@@ -0,0 +1,168 @@
+package ghapi
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func makeTestJournal(t *testing.T) (*Journal, string, func()) {
+	dir, err := ioutil.TempDir("", "ghapi-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "events.jsonl")
+
+	journal, err := OpenJournal(path)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return journal, path, func() {
+		if closeErr := journal.Close(); closeErr != nil {
+			t.Error(closeErr)
+		}
+		os.RemoveAll(dir)
+	}
+}
+
+func TestJournal_Replay(t *testing.T) {
+	journal, path, cleanup := makeTestJournal(t)
+	defer cleanup()
+
+	now := time.Date(2017, 7, 14, 3, 0, 0, 0, time.UTC)
+	journal.now = func() time.Time { return now }
+
+	record := func(delivery *Delivery) {
+		expectNil(t, journal.Record(delivery), "Record")
+		now = now.Add(time.Hour)
+	}
+	record(makeQueuedDelivery("octocat/a", 0))
+	record(&Delivery{ID: "status-1", EventType: StatusEventType, Body: []byte(`{"repository":{"full_name":"octocat/a"}}`)})
+	record(makeQueuedDelivery("octocat/b", 1))
+	record(makeQueuedDelivery("octocat/a", 2))
+
+	var ids []string
+	replay := func(filter ReplayFilter) int {
+		ids = nil
+		n, err := ReplayJournal(context.Background(), path, filter, func(ctx context.Context, delivery *Delivery) error {
+			ids = append(ids, delivery.ID)
+			return nil
+		})
+		expectNil(t, err, "err")
+		return n
+	}
+
+	expect(t, 4, replay(ReplayFilter{}), "replay all")
+
+	expect(t, 3, replay(ReplayFilter{EventTypes: []GitHubEventType{PushEventType}}), "replay push")
+	expect(t, "octocat/a-0", ids[0], "ids[0]")
+
+	expect(t, 2, replay(ReplayFilter{EventTypes: []GitHubEventType{PushEventType}, Repositories: []string{"octocat/a"}}), "replay push to a")
+	expect(t, "octocat/a-2", ids[1], "ids[1]")
+
+	window := ReplayFilter{
+		Since: time.Date(2017, 7, 14, 4, 0, 0, 0, time.UTC),
+		Until: time.Date(2017, 7, 14, 6, 0, 0, 0, time.UTC),
+	}
+	expect(t, 2, replay(window), "replay window")
+	expect(t, "status-1", ids[0], "ids[0]")
+	expect(t, "octocat/b-1", ids[1], "ids[1]")
+}
+
+func TestJournal_ReplaysThroughWebhookServer(t *testing.T) {
+	journal, path, cleanup := makeTestJournal(t)
+	defer cleanup()
+
+	s := NewWebhookServer(webhookTestSecret)
+	s.Journal = journal
+
+	var refs []string
+	s.OnPush(func(ctx context.Context, payload *PushEventPayload) error {
+		refs = append(refs, payload.Ref)
+		return nil
+	})
+
+	serveWebhook(s, makeDeliveryRequest(t, testDeliveryID))
+	expect(t, 1, len(refs), "len(refs)")
+
+	n, err := ReplayJournal(context.Background(), path, ReplayFilter{}, s.HandleDelivery)
+	expectNil(t, err, "err")
+	expect(t, 1, n, "n")
+	expect(t, 2, len(refs), "len(refs)")
+	expect(t, "refs/heads/master", refs[1], "refs[1]")
+}
+
+func TestJournal_ReplayKeepsExactBody(t *testing.T) {
+	journal, path, cleanup := makeTestJournal(t)
+	defer cleanup()
+
+	s := NewWebhookServer(webhookTestSecret)
+	s.Journal = journal
+
+	const body = "{\n  \"ref\": \"refs/heads/<a&b>\"\n}"
+	req := makeWebhookRequest(t, PushEventType, body)
+	req.Header.Set("X-GitHub-Delivery", testDeliveryID)
+	expect(t, 204, serveWebhook(s, req).Code, "serveWebhook")
+
+	var replayed *Delivery
+	n, err := ReplayJournal(context.Background(), path, ReplayFilter{}, func(ctx context.Context, delivery *Delivery) error {
+		replayed = delivery
+		return nil
+	})
+	expectNil(t, err, "err")
+	expect(t, 1, n, "n")
+	expect(t, body, string(replayed.Body), "replayed.Body")
+	expectNil(t, s.Policy.ValidateEvent(replayed.Body, replayed.Header), "ValidateEvent")
+}
+
+func TestReplayJournal_StopsOnError(t *testing.T) {
+	journal, path, cleanup := makeTestJournal(t)
+	defer cleanup()
+
+	expectNil(t, journal.Record(makeQueuedDelivery("octocat/a", 0)), "Record")
+	expectNil(t, journal.Record(makeQueuedDelivery("octocat/a", 1)), "Record")
+
+	errHandler := errors.New("handler failed")
+	n, err := ReplayJournal(context.Background(), path, ReplayFilter{}, func(ctx context.Context, delivery *Delivery) error {
+		return errHandler
+	})
+	expect(t, 0, n, "n")
+	if !errors.Is(err, errHandler) {
+		t.Fatalf("expected handler error, got %v", err)
+	}
+}
+
+func TestReplayJournal_IgnoresIncompleteLastLine(t *testing.T) {
+	journal, path, cleanup := makeTestJournal(t)
+	defer cleanup()
+
+	expectNil(t, journal.Record(makeQueuedDelivery("octocat/a", 0)), "Record")
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.Write([]byte(`{"received_at":"2017-07-14T03:00:00Z","event_type":"pu`)); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	n, err := ReplayJournal(context.Background(), path, ReplayFilter{}, func(ctx context.Context, delivery *Delivery) error {
+		return nil
+	})
+	expectNil(t, err, "err")
+	expect(t, 1, n, "n")
+}
+
+func TestJournal_RecordRejectsInvalidJSON(t *testing.T) {
+	journal, _, cleanup := makeTestJournal(t)
+	defer cleanup()
+
+	err := journal.Record(&Delivery{ID: "1", EventType: PushEventType, Body: []byte("payload=%7B%7D")})
+	expectNotNil(t, err, "err")
+}
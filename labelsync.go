@@ -0,0 +1,186 @@
+package ghapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LabelSpec is a label SyncLabels makes sure exists.
+type LabelSpec struct {
+	// Name is the label's name.
+	Name string
+	// Color is a 6 character hex code. A leading # is ignored.
+	Color string
+	// Description is the label's description. An empty Description removes an existing label's description.
+	Description string
+	// Aliases are previous names of the label. If no label named Name exists, the first existing alias is renamed to
+	// Name, so issues keep the label.
+	Aliases []string
+}
+
+// SyncLabelsOptions controls SyncLabels.
+type SyncLabelsOptions struct {
+	// Prune deletes existing labels which don't match a LabelSpec's name or aliases.
+	Prune bool
+	// DryRun returns the changes which would be made without making them.
+	DryRun bool
+}
+
+// LabelAction is the kind of change SyncLabels makes to a label.
+type LabelAction string
+
+const (
+	// LabelCreate creates a label; "create".
+	LabelCreate LabelAction = "create"
+	// LabelUpdate renames a label or changes its color or description; "update".
+	LabelUpdate LabelAction = "update"
+	// LabelDelete deletes a label; "delete".
+	LabelDelete LabelAction = "delete"
+)
+
+// LabelChange is a change made, or planned, by SyncLabels.
+type LabelChange struct {
+	Action LabelAction
+	// Name is the label's name after the change. For LabelDelete it's the deleted label's name.
+	Name string
+	// OldName is the label's name before a rename, otherwise it's empty.
+	OldName     string
+	Color       string
+	Description string
+}
+
+// String returns a one line description of the change, for example to print a dry-run plan.
+func (c LabelChange) String() string {
+	switch c.Action {
+	case LabelCreate:
+		return fmt.Sprintf("create %q color=%s description=%q", c.Name, c.Color, c.Description)
+	case LabelUpdate:
+		if c.OldName != "" {
+			return fmt.Sprintf("rename %q to %q color=%s description=%q", c.OldName, c.Name, c.Color, c.Description)
+		}
+		return fmt.Sprintf("update %q color=%s description=%q", c.Name, c.Color, c.Description)
+	case LabelDelete:
+		return fmt.Sprintf("delete %q", c.Name)
+	}
+	return fmt.Sprintf("%s %q", c.Action, c.Name)
+}
+
+// SyncLabels makes the repository's labels match desired, creating missing labels, renaming labels found by an alias,
+// and updating colors and descriptions. With opts.Prune, labels not in desired are deleted. It returns the changes
+// made, or with opts.DryRun the changes which would be made. A nil opts is the same as &SyncLabelsOptions{}.
+//
+// Label names are compared case-insensitively, as GitHub does; a label which only differs by case is renamed.
+//
+// If an error occurs the changes made so far are returned with the error. Calling SyncLabels again continues where
+// it stopped.
+func (api *RepositoryAPI) SyncLabels(desired []LabelSpec, opts *SyncLabelsOptions) ([]LabelChange, error) {
+	return api.SyncLabelsContext(context.Background(), desired, opts)
+}
+
+// SyncLabelsContext is like SyncLabels but uses the provided context.
+func (api *RepositoryAPI) SyncLabelsContext(ctx context.Context, desired []LabelSpec, opts *SyncLabelsOptions) ([]LabelChange, error) {
+	if opts == nil {
+		opts = &SyncLabelsOptions{}
+	}
+
+	existing, err := api.GetLabelsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := planLabelChanges(existing, desired, opts.Prune)
+	if err != nil {
+		return nil, err
+	}
+	if opts.DryRun {
+		return plan, nil
+	}
+
+	var applied []LabelChange
+	for _, change := range plan {
+		switch change.Action {
+		case LabelCreate:
+			err = api.CreateLabelWithDescriptionContext(ctx, change.Name, change.Color, change.Description)
+		case LabelUpdate:
+			origName := change.OldName
+			if origName == "" {
+				origName = change.Name
+			}
+			err = api.UpdateLabelWithDescriptionContext(ctx, origName, change.Name, change.Color, change.Description)
+		case LabelDelete:
+			err = api.DeleteLabelContext(ctx, change.Name)
+		}
+		if err != nil {
+			return applied, fmt.Errorf("%s: %w", change, err)
+		}
+		applied = append(applied, change)
+	}
+
+	return applied, nil
+}
+
+// planLabelChanges returns the changes which make existing match desired. Renames and updates come first, then
+// creates, then deletes.
+func planLabelChanges(existing []IssueLabel, desired []LabelSpec, prune bool) ([]LabelChange, error) {
+	byName := make(map[string]IssueLabel, len(existing))
+	for _, label := range existing {
+		byName[strings.ToLower(label.Name)] = label
+	}
+
+	// every name and alias must refer to one spec, otherwise the result depends on the order of desired
+	names := make(map[string]string)
+	for _, spec := range desired {
+		if spec.Name == "" {
+			return nil, errors.New("SyncLabels: label name is required")
+		}
+		for _, name := range append([]string{spec.Name}, spec.Aliases...) {
+			key := strings.ToLower(name)
+			if other, ok := names[key]; ok {
+				return nil, fmt.Errorf("SyncLabels: %q is used by both %q and %q", name, other, spec.Name)
+			}
+			names[key] = spec.Name
+		}
+	}
+
+	claimed := make(map[string]bool)
+	var updates, creates, deletes []LabelChange
+
+	for _, spec := range desired {
+		color := strings.ToLower(strings.TrimPrefix(spec.Color, "#"))
+
+		label, ok := byName[strings.ToLower(spec.Name)]
+		if !ok {
+			for _, alias := range spec.Aliases {
+				if label, ok = byName[strings.ToLower(alias)]; ok {
+					break
+				}
+			}
+		}
+
+		if !ok {
+			creates = append(creates, LabelChange{Action: LabelCreate, Name: spec.Name, Color: color, Description: spec.Description})
+			continue
+		}
+		claimed[strings.ToLower(label.Name)] = true
+
+		change := LabelChange{Action: LabelUpdate, Name: spec.Name, Color: color, Description: spec.Description}
+		if label.Name != spec.Name {
+			change.OldName = label.Name
+		}
+		if change.OldName != "" || !strings.EqualFold(label.Color, color) || label.Description != spec.Description {
+			updates = append(updates, change)
+		}
+	}
+
+	if prune {
+		for _, label := range existing {
+			if !claimed[strings.ToLower(label.Name)] {
+				deletes = append(deletes, LabelChange{Action: LabelDelete, Name: label.Name})
+			}
+		}
+	}
+
+	return append(append(updates, creates...), deletes...), nil
+}
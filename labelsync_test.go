@@ -0,0 +1,196 @@
+package ghapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// makeLabelsTestServer returns a server with an in-memory labels endpoint and a function returning the write requests
+// it received.
+func makeLabelsTestServer(t *testing.T, labels []IssueLabel) (*httptest.Server, func() []string) {
+	var mtx sync.Mutex
+	var writes []string
+
+	const path = "/repos/test_owner/test_repository/labels"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		// label descriptions are only read and written with the preview header
+		if r.Method != "DELETE" {
+			expect(t, symmetraPreviewAcceptHeader, r.Header.Get("Accept"), "Accept")
+		}
+
+		if r.Method == "GET" && r.URL.Path == path {
+			writeTestJSON(t, w, labels)
+			return
+		}
+
+		var body struct {
+			Name        string  `json:"name"`
+			Color       string  `json:"color"`
+			Description *string `json:"description"`
+		}
+		if r.Method != "DELETE" {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+		}
+		name, err := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), path+"/"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch r.Method {
+		case "POST":
+			writes = append(writes, "POST "+body.Name+" "+body.Color+" "+*body.Description)
+			labels = append(labels, IssueLabel{Name: body.Name, Color: body.Color, Description: *body.Description})
+			w.WriteHeader(201)
+		case "PATCH":
+			writes = append(writes, "PATCH "+name+" -> "+body.Name+" "+body.Color+" "+*body.Description)
+			w.WriteHeader(200)
+		case "DELETE":
+			writes = append(writes, "DELETE "+name)
+			w.WriteHeader(204)
+			return
+		}
+		if _, err = w.Write([]byte("{}")); err != nil {
+			t.Fatal(err)
+		}
+	}))
+
+	return ts, func() []string {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return append([]string(nil), writes...)
+	}
+}
+
+func testLabelSpecs() []LabelSpec {
+	return []LabelSpec{
+		{Name: "bug", Color: "#D73A4A", Description: "Something isn't working", Aliases: []string{"defect"}},
+		{Name: "enhancement", Color: "a2eeef", Description: "New feature or request"},
+		{Name: "good first issue", Color: "7057ff"},
+		{Name: "question", Color: "d876e3", Aliases: []string{"help"}},
+	}
+}
+
+func testExistingLabels() []IssueLabel {
+	return []IssueLabel{
+		{Name: "defect", Color: "ee0701"},
+		{Name: "Enhancement", Color: "a2eeef", Description: "New feature or request"},
+		{Name: "good first issue", Color: "7057ff"},
+		{Name: "wontfix", Color: "ffffff"},
+	}
+}
+
+func TestRepositoryAPI_SyncLabels(t *testing.T) {
+	ts, writes := makeLabelsTestServer(t, testExistingLabels())
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken)
+	changes, err := api.Repository.SyncLabels(testLabelSpecs(), &SyncLabelsOptions{Prune: true})
+	expectNil(t, err, "err")
+	expect(t, 4, len(changes), "len(changes)")
+
+	expected := []string{
+		"PATCH defect -> bug d73a4a Something isn't working",
+		"PATCH Enhancement -> enhancement a2eeef New feature or request",
+		"POST question d876e3 ",
+		"DELETE wontfix",
+	}
+	actual := writes()
+	expect(t, len(expected), len(actual), "len(writes)")
+	for i := range expected {
+		expect(t, expected[i], actual[i], "writes[i]")
+	}
+}
+
+func TestRepositoryAPI_SyncLabels_DryRun(t *testing.T) {
+	ts, writes := makeLabelsTestServer(t, testExistingLabels())
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken)
+	plan, err := api.Repository.SyncLabels(testLabelSpecs(), &SyncLabelsOptions{DryRun: true})
+	expectNil(t, err, "err")
+	expect(t, 0, len(writes()), "len(writes)")
+
+	expected := []string{
+		`rename "defect" to "bug" color=d73a4a description="Something isn't working"`,
+		`rename "Enhancement" to "enhancement" color=a2eeef description="New feature or request"`,
+		`create "question" color=d876e3 description=""`,
+	}
+	expect(t, len(expected), len(plan), "len(plan)")
+	for i := range expected {
+		expect(t, expected[i], plan[i].String(), "plan[i]")
+	}
+}
+
+func TestRepositoryAPI_SyncLabels_RejectsDuplicateNames(t *testing.T) {
+	ts, writes := makeLabelsTestServer(t, testExistingLabels())
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken)
+	_, err := api.Repository.SyncLabels([]LabelSpec{
+		{Name: "bug", Color: "d73a4a"},
+		{Name: "defect", Color: "ee0701", Aliases: []string{"Bug"}},
+	}, nil)
+	expectNotNil(t, err, "err")
+	expect(t, 0, len(writes()), "len(writes)")
+}
+
+func TestRepositoryAPI_CreateLabel(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+
+		expectNil(t, err, "err")
+		expect(t, "POST", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/labels", r.URL.Path, "r.URL.Path")
+		expect(t, symmetraPreviewAcceptHeader, r.Header.Get("Accept"), "Accept")
+		expect(t, `{"name":"bug","color":"d73a4a","description":"Something isn't working"}`, string(b), "r.Body")
+
+		w.WriteHeader(201)
+	})
+	defer ts.Close()
+
+	err := api.Repository.CreateLabelWithDescription("bug", "d73a4a", "Something isn't working")
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+}
+
+func TestRepositoryAPI_UpdateLabel(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+
+		expectNil(t, err, "err")
+		expect(t, "PATCH", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/labels/good%20first%20issue", r.URL.EscapedPath(), "r.URL.EscapedPath()")
+		expect(t, symmetraPreviewAcceptHeader, r.Header.Get("Accept"), "Accept")
+		expect(t, `{"name":"beginner","color":"7057ff"}`, string(b), "r.Body")
+
+		w.WriteHeader(200)
+	})
+	defer ts.Close()
+
+	err := api.Repository.UpdateLabel("good first issue", "beginner", "7057ff")
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+}
+
+func TestRepositoryAPI_DeleteLabel(t *testing.T) {
+	ts, writes := makeLabelsTestServer(t, testExistingLabels())
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken)
+	expectNil(t, api.Repository.DeleteLabel("good first issue"), "DeleteLabel")
+	expect(t, "DELETE good first issue", writes()[0], "writes()[0]")
+}
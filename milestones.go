@@ -0,0 +1,243 @@
+package ghapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Milestone contains milestone information. This type is used in IssueResponse, PullRequestResponse and
+// MilestoneEventPayload, and returned by MilestonesAPI.
+type Milestone struct {
+	URL          string     `json:"url"`
+	HTMLURL      string     `json:"html_url"`
+	LabelsURL    string     `json:"labels_url"`
+	ID           int        `json:"id"`
+	Number       int        `json:"number"`
+	State        string     `json:"state"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	Creator      User       `json:"creator"`
+	OpenIssues   int        `json:"open_issues"`
+	ClosedIssues int        `json:"closed_issues"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	ClosedAt     *time.Time `json:"closed_at"`
+	DueOn        *time.Time `json:"due_on"`
+}
+
+// MilestoneRequest contains the fields of a milestone to create or edit. Nil fields are left unchanged when editing;
+// Title is required when creating.
+type MilestoneRequest struct {
+	Title *string `json:"title,omitempty"`
+	// State is "open" or "closed".
+	State       *string    `json:"state,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	DueOn       *time.Time `json:"due_on,omitempty"`
+}
+
+// MilestoneListOptions filters and sorts the milestones returned by ListMilestones. Zero values use GitHub's
+// defaults, which list open milestones sorted by due date, earliest first.
+type MilestoneListOptions struct {
+	// State is "open", "closed", or "all".
+	State string
+	// Sort is "due_on" or "completeness".
+	Sort string
+	// Direction is "asc" or "desc".
+	Direction string
+}
+
+func (o *MilestoneListOptions) query() string {
+	if o == nil {
+		return ""
+	}
+
+	q := url.Values{}
+	if o.State != "" {
+		q.Set("state", o.State)
+	}
+	if o.Sort != "" {
+		q.Set("sort", o.Sort)
+	}
+	if o.Direction != "" {
+		q.Set("direction", o.Direction)
+	}
+
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
+
+// ListMilestones lists the repository's milestones matching filter. A nil filter lists open milestones.
+func (api *MilestonesAPI) ListMilestones(filter *MilestoneListOptions) ([]Milestone, error) {
+	return api.ListMilestonesContext(context.Background(), filter)
+}
+
+// ListMilestonesContext is like ListMilestones but uses the provided context.
+func (api *MilestonesAPI) ListMilestonesContext(ctx context.Context, filter *MilestoneListOptions) ([]Milestone, error) {
+	var allMilestones []Milestone
+	if err := api.ListMilestonesPagesContext(ctx, filter, nil).All(&allMilestones); err != nil {
+		return nil, err
+	}
+
+	return allMilestones, nil
+}
+
+// ListMilestonesPages returns a PageIterator over the repository's milestones matching filter. Each page decodes to
+// []Milestone.
+func (api *MilestonesAPI) ListMilestonesPages(filter *MilestoneListOptions, opts *ListOptions) *PageIterator {
+	return api.ListMilestonesPagesContext(context.Background(), filter, opts)
+}
+
+// ListMilestonesPagesContext is like ListMilestonesPages but uses ctx for each page request.
+func (api *MilestonesAPI) ListMilestonesPagesContext(ctx context.Context, filter *MilestoneListOptions, opts *ListOptions) *PageIterator {
+	url := api.getURL("/repos/:owner/:repo/milestones" + filter.query())
+	return api.NewPageIteratorContext(ctx, url, opts)
+}
+
+// GetMilestone gets a milestone by milestone number.
+func (api *MilestonesAPI) GetMilestone(number int) (*Milestone, error) {
+	return api.GetMilestoneContext(context.Background(), number)
+}
+
+// GetMilestoneContext is like GetMilestone but uses the provided context.
+func (api *MilestonesAPI) GetMilestoneContext(ctx context.Context, number int) (*Milestone, error) {
+	resp, err := api.httpGet(ctx, api.milestoneURL(number))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var milestone Milestone
+
+	j := json.NewDecoder(resp.Body)
+	if err = j.Decode(&milestone); err != nil {
+		return nil, err
+	}
+
+	return &milestone, nil
+}
+
+// CreateMilestone creates a milestone. milestone.Title is required.
+func (api *MilestonesAPI) CreateMilestone(milestone MilestoneRequest) (*Milestone, error) {
+	return api.CreateMilestoneContext(context.Background(), milestone)
+}
+
+// CreateMilestoneContext is like CreateMilestone but uses the provided context.
+func (api *MilestonesAPI) CreateMilestoneContext(ctx context.Context, milestone MilestoneRequest) (*Milestone, error) {
+	if milestone.Title == nil || *milestone.Title == "" {
+		return nil, errors.New("CreateMilestone: milestone.Title is required")
+	}
+
+	return api.sendMilestone(ctx, "POST", api.getURL("/repos/:owner/:repo/milestones"), milestone)
+}
+
+// EditMilestone updates a milestone by milestone number. Only the non-nil fields of milestone are changed.
+func (api *MilestonesAPI) EditMilestone(number int, milestone MilestoneRequest) (*Milestone, error) {
+	return api.EditMilestoneContext(context.Background(), number, milestone)
+}
+
+// EditMilestoneContext is like EditMilestone but uses the provided context.
+func (api *MilestonesAPI) EditMilestoneContext(ctx context.Context, number int, milestone MilestoneRequest) (*Milestone, error) {
+	return api.sendMilestone(ctx, "PATCH", api.milestoneURL(number), milestone)
+}
+
+// DeleteMilestone deletes a milestone by milestone number. Issues in the milestone are left without one.
+func (api *MilestonesAPI) DeleteMilestone(number int) error {
+	return api.DeleteMilestoneContext(context.Background(), number)
+}
+
+// DeleteMilestoneContext is like DeleteMilestone but uses the provided context.
+func (api *MilestonesAPI) DeleteMilestoneContext(ctx context.Context, number int) error {
+	resp, err := api.httpDelete(ctx, api.milestoneURL(number))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// ListMilestoneLabels lists the labels of the issues in a milestone.
+func (api *MilestonesAPI) ListMilestoneLabels(number int) ([]IssueLabel, error) {
+	return api.ListMilestoneLabelsContext(context.Background(), number)
+}
+
+// ListMilestoneLabelsContext is like ListMilestoneLabels but uses the provided context.
+func (api *MilestonesAPI) ListMilestoneLabelsContext(ctx context.Context, number int) ([]IssueLabel, error) {
+	var allLabels []IssueLabel
+	if err := api.ListMilestoneLabelsPagesContext(ctx, number, nil).All(&allLabels); err != nil {
+		return nil, err
+	}
+
+	return allLabels, nil
+}
+
+// ListMilestoneLabelsPages returns a PageIterator over the labels of the issues in a milestone. Each page decodes to
+// []IssueLabel.
+func (api *MilestonesAPI) ListMilestoneLabelsPages(number int, opts *ListOptions) *PageIterator {
+	return api.ListMilestoneLabelsPagesContext(context.Background(), number, opts)
+}
+
+// ListMilestoneLabelsPagesContext is like ListMilestoneLabelsPages but uses ctx for each page request.
+func (api *MilestonesAPI) ListMilestoneLabelsPagesContext(ctx context.Context, number int, opts *ListOptions) *PageIterator {
+	return api.NewPageIteratorContext(ctx, api.milestoneURL(number)+"/labels", opts)
+}
+
+// MoveOpenIssues moves the open issues and pull requests in milestone from to milestone to, for example to carry
+// unfinished work over to the next release. It returns the number of issues moved.
+func (api *MilestonesAPI) MoveOpenIssues(from, to int) (int, error) {
+	return api.MoveOpenIssuesContext(context.Background(), from, to)
+}
+
+// MoveOpenIssuesContext is like MoveOpenIssues but uses the provided context.
+func (api *MilestonesAPI) MoveOpenIssuesContext(ctx context.Context, from, to int) (int, error) {
+	issues := IssueAPI{RepositoryInfo: api.RepositoryInfo}
+
+	open, err := issues.ListIssuesContext(ctx, &IssueListOptions{Milestone: strconv.Itoa(from), State: "open"})
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, issue := range open {
+		if _, err = issues.EditIssueContext(ctx, issue.Number, IssueRequest{Milestone: &to}); err != nil {
+			return moved, fmt.Errorf("moving issue #%d: %w", issue.Number, err)
+		}
+		moved++
+	}
+
+	return moved, nil
+}
+
+func (api *MilestonesAPI) milestoneURL(number int) string {
+	return api.getURL("/repos/:owner/:repo/milestones/" + strconv.Itoa(number))
+}
+
+func (api *MilestonesAPI) sendMilestone(ctx context.Context, method, url string, milestone MilestoneRequest) (*Milestone, error) {
+	b, err := json.Marshal(milestone)
+	if err != nil {
+		return nil, err
+	}
+	body := string(b)
+
+	resp, err := api.doHTTPRequest(ctx, method, url, &body, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var updated Milestone
+
+	j := json.NewDecoder(resp.Body)
+	if err = j.Decode(&updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
@@ -0,0 +1,163 @@
+package ghapi
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+const milestone1Response = `{
+  "url": "https://api.github.com/repos/octocat/Hello-World/milestones/1",
+  "html_url": "https://github.com/octocat/Hello-World/milestones/v1.0",
+  "labels_url": "https://api.github.com/repos/octocat/Hello-World/milestones/1/labels",
+  "id": 1002604,
+  "number": 1,
+  "state": "open",
+  "title": "v1.0",
+  "description": "Tracking milestone for version 1.0",
+  "creator": {"login": "octocat", "id": 1},
+  "open_issues": 4,
+  "closed_issues": 8,
+  "created_at": "2011-04-10T20:09:31Z",
+  "updated_at": "2014-03-03T18:58:10Z",
+  "closed_at": null,
+  "due_on": "2012-10-09T23:39:01Z"
+}`
+
+func TestMilestonesAPI_ListMilestones(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "GET", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/milestones", r.URL.Path, "r.URL.Path")
+		expect(t, "direction=desc&sort=completeness&state=all", r.URL.RawQuery, "r.URL.RawQuery")
+
+		_, err := w.Write([]byte("[" + milestone1Response + "]"))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	milestones, err := api.Milestones.ListMilestones(&MilestoneListOptions{State: "all", Sort: "completeness", Direction: "desc"})
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, 1, len(milestones), "len(milestones)")
+	expect(t, "v1.0", milestones[0].Title, "milestones[0].Title")
+	expect(t, date("2012-10-09T23:39:01Z"), milestones[0].DueOn, "milestones[0].DueOn")
+	expectNil(t, milestones[0].ClosedAt, "milestones[0].ClosedAt")
+}
+
+func TestMilestonesAPI_CreateMilestone(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+
+		expectNil(t, err, "err")
+		expect(t, "POST", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/milestones", r.URL.Path, "r.URL.Path")
+		expect(t, `{"title":"v1.0","due_on":"2012-10-09T23:39:01Z"}`, string(b), "r.Body")
+
+		w.WriteHeader(201)
+		_, err = w.Write([]byte(milestone1Response))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	title, dueOn := "v1.0", date("2012-10-09T23:39:01Z")
+	milestone, err := api.Milestones.CreateMilestone(MilestoneRequest{Title: &title, DueOn: &dueOn})
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, 1, milestone.Number, "milestone.Number")
+	expect(t, "octocat", milestone.Creator.Login, "milestone.Creator.Login")
+}
+
+func TestMilestonesAPI_EditMilestone(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+
+		expectNil(t, err, "err")
+		expect(t, "PATCH", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/milestones/1", r.URL.Path, "r.URL.Path")
+		expect(t, `{"state":"closed"}`, string(b), "r.Body")
+
+		_, err = w.Write([]byte(milestone1Response))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	state := "closed"
+	_, err := api.Milestones.EditMilestone(1, MilestoneRequest{State: &state})
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+}
+
+func TestMilestonesAPI_DeleteMilestone(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "DELETE", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/milestones/1", r.URL.Path, "r.URL.Path")
+
+		w.WriteHeader(204)
+	})
+	defer ts.Close()
+
+	err := api.Milestones.DeleteMilestone(1)
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+}
+
+func TestMilestonesAPI_ListMilestoneLabels(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "GET", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/milestones/1/labels", r.URL.Path, "r.URL.Path")
+
+		_, err := w.Write([]byte(`[{"id":208045946,"name":"bug","color":"f29513"}]`))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	labels, err := api.Milestones.ListMilestoneLabels(1)
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, 1, len(labels), "len(labels)")
+	expect(t, "bug", labels[0].Name, "labels[0].Name")
+}
+
+func TestMilestonesAPI_MoveOpenIssues(t *testing.T) {
+	var mtx sync.Mutex
+	var requests []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		expectNil(t, err, "err")
+
+		mtx.Lock()
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+string(b))
+		mtx.Unlock()
+
+		if r.Method == "GET" {
+			_, err = w.Write([]byte(`[{"number":3},{"number":5}]`))
+		} else {
+			_, err = w.Write([]byte(`{}`))
+		}
+		expectNil(t, err, "err")
+	}))
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken)
+	moved, err := api.Milestones.MoveOpenIssues(1, 2)
+	expectNil(t, err, "err")
+	expect(t, 2, moved, "moved")
+
+	expected := []string{
+		"GET /repos/test_owner/test_repository/issues?milestone=1&state=open ",
+		`PATCH /repos/test_owner/test_repository/issues/3 {"milestone":2}`,
+		`PATCH /repos/test_owner/test_repository/issues/5 {"milestone":2}`,
+	}
+	expect(t, len(expected), len(requests), "len(requests)")
+	for i := range expected {
+		expect(t, expected[i], requests[i], "requests[i]")
+	}
+}
@@ -1,7 +1,7 @@
 package ghapi
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 )
 
@@ -20,66 +20,61 @@ type ListTeamsResponse struct {
 
 // ListTeams lists and organization's teams. Note: to use this API call your authtoken must have org:read permission.
 func (api *OrganizationAPI) ListTeams() ([]ListTeamsResponse, error) {
-	var allTeams []ListTeamsResponse
-	for page := 1; ; page++ {
-		url := api.addBaseURL(fmt.Sprintf("/orgs/%s/teams?page=%d", api.Organization, page))
+	return api.ListTeamsContext(context.Background())
+}
 
-		resp, err := api.httpGet(url)
-		//resp.Header["Link"] // TODO (judwhite), get next page until last
-		//<url>; rel="last", <url>; rel="first", <url>; rel="prev", <url>; rel="next"
-		if err != nil {
-			switch val := err.(type) {
-			case *ErrHTTPError:
-				if val.StatusCode == 403 {
-					val.Message = "does your authtoken have org:read permission?"
-					return nil, val
-				}
+// ListTeamsContext is like ListTeams but uses the provided context.
+func (api *OrganizationAPI) ListTeamsContext(ctx context.Context) ([]ListTeamsResponse, error) {
+	var allTeams []ListTeamsResponse
+	if err := api.ListTeamsPagesContext(ctx, nil).All(&allTeams); err != nil {
+		switch val := err.(type) {
+		case *ErrHTTPError:
+			if val.StatusCode == 403 {
+				val.Message = "does your authtoken have org:read permission?"
+				return nil, val
 			}
-			return nil, err
-		}
-
-		teams := []ListTeamsResponse{}
-		if err = json.NewDecoder(resp.Body).Decode(&teams); err != nil {
-			resp.Body.Close()
-			return nil, err
-		}
-		resp.Body.Close()
-
-		allTeams = append(allTeams, teams...)
-		if len(teams) == 0 || resp.Header.Get("Link") == "" {
-			break
 		}
+		return nil, err
 	}
 
 	return allTeams, nil
 }
 
+// ListTeamsPages returns a PageIterator over an organization's teams. Each page decodes to []ListTeamsResponse.
+func (api *OrganizationAPI) ListTeamsPages(opts *ListOptions) *PageIterator {
+	return api.ListTeamsPagesContext(context.Background(), opts)
+}
+
+// ListTeamsPagesContext is like ListTeamsPages but uses ctx for each page request.
+func (api *OrganizationAPI) ListTeamsPagesContext(ctx context.Context, opts *ListOptions) *PageIterator {
+	url := api.addBaseURL(fmt.Sprintf("/orgs/%s/teams", api.Organization))
+	return api.NewPageIteratorContext(ctx, url, opts)
+}
+
 // ListTeamMembers list team members for the specified teamID.
 // role is "member" (normal members of the team), "maintainer" (team maintainers), or "all".
 func (api *OrganizationAPI) ListTeamMembers(teamID int, role string) ([]User, error) {
-	var allTeamMembers []User
-	for page := 1; ; page++ {
-		url := api.addBaseURL(fmt.Sprintf("/teams/%d/members?role=%s&page=%d", teamID, role, page))
-
-		resp, err := api.httpGet(url)
-		//resp.Header["Link"] // TODO (judwhite), get next page until last
-		//<url>; rel="last", <url>; rel="first", <url>; rel="prev", <url>; rel="next"
-		if err != nil {
-			return nil, err
-		}
-
-		teamMembers := []User{}
-		if err = json.NewDecoder(resp.Body).Decode(&teamMembers); err != nil {
-			resp.Body.Close()
-			return nil, err
-		}
-		resp.Body.Close()
+	return api.ListTeamMembersContext(context.Background(), teamID, role)
+}
 
-		allTeamMembers = append(allTeamMembers, teamMembers...)
-		if len(teamMembers) == 0 || resp.Header.Get("Link") == "" {
-			break
-		}
+// ListTeamMembersContext is like ListTeamMembers but uses the provided context.
+func (api *OrganizationAPI) ListTeamMembersContext(ctx context.Context, teamID int, role string) ([]User, error) {
+	var allTeamMembers []User
+	if err := api.ListTeamMembersPagesContext(ctx, teamID, role, nil).All(&allTeamMembers); err != nil {
+		return nil, err
 	}
 
 	return allTeamMembers, nil
 }
+
+// ListTeamMembersPages returns a PageIterator over the members of the specified teamID. Each page decodes to []User.
+// role is "member" (normal members of the team), "maintainer" (team maintainers), or "all".
+func (api *OrganizationAPI) ListTeamMembersPages(teamID int, role string, opts *ListOptions) *PageIterator {
+	return api.ListTeamMembersPagesContext(context.Background(), teamID, role, opts)
+}
+
+// ListTeamMembersPagesContext is like ListTeamMembersPages but uses ctx for each page request.
+func (api *OrganizationAPI) ListTeamMembersPagesContext(ctx context.Context, teamID int, role string, opts *ListOptions) *PageIterator {
+	url := api.addBaseURL(fmt.Sprintf("/teams/%d/members?role=%s", teamID, role))
+	return api.NewPageIteratorContext(ctx, url, opts)
+}
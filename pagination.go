@@ -0,0 +1,314 @@
+package ghapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	neturl "net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Links contains the URLs parsed from an RFC 5988 "Link" header returned by paginated GitHub API calls.
+// See https://developer.github.com/v3/#pagination.
+type Links struct {
+	Next  string
+	Prev  string
+	First string
+	Last  string
+}
+
+// ListOptions controls how a PageIterator walks a paginated list. A nil *ListOptions uses GitHub's defaults and
+// reads every page.
+type ListOptions struct {
+	// PerPage is the number of items requested per page. GitHub defaults to 30 and allows a maximum of 100.
+	PerPage int
+	// MaxPages stops the iterator after this many pages have been requested. Zero means no limit.
+	MaxPages int
+	// MaxItems stops the iterator after this many items have been decoded. The last page is truncated
+	// if necessary. Zero means no limit.
+	MaxItems int
+}
+
+// PageIterator walks a paginated GitHub list by following the rel="next" URL from each response's "Link" header.
+//
+//	it := api.Repository.ListCommitsPages(nil)
+//	for it.Next() {
+//		var commits []RepositoryCommit
+//		if err := it.Decode(&commits); err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type PageIterator struct {
+	apiInfo      *APIInfo
+	ctx          context.Context
+	nextURL      string
+	accept       string // Accept header for preview APIs; empty uses the default
+	rateResource string // rate limit resource to wait on before each page request; empty doesn't wait
+	opts         ListOptions
+	resp         *http.Response
+	links        Links
+	pages        int
+	items        int
+	done         bool
+	err          error
+}
+
+// ErrPageNotRead is returned by PageIterator.Decode when Next has not been called or returned false.
+var ErrPageNotRead = errors.New("no page to decode; call Next first")
+
+// ParseLinkHeader parses an RFC 5988 "Link" header value and returns the next, prev, first and last URLs.
+// Relations which aren't present are returned as empty strings.
+//
+//	<https://api.github.com/resource?page=2>; rel="next", <https://api.github.com/resource?page=5>; rel="last"
+func ParseLinkHeader(header string) Links {
+	var links Links
+
+	for header != "" {
+		start := strings.IndexByte(header, '<')
+		if start == -1 {
+			break
+		}
+		end := strings.IndexByte(header[start:], '>')
+		if end == -1 {
+			break
+		}
+		end += start
+
+		link := header[start+1 : end]
+		header = header[end+1:]
+
+		// parameters run until the next link-value; a '<' can't appear in a parameter outside of a quoted string
+		params := header
+		if next := indexUnquoted(header, ','); next != -1 {
+			params = header[:next]
+			header = header[next+1:]
+		} else {
+			header = ""
+		}
+
+		for _, param := range strings.Split(params, ";") {
+			param = strings.TrimSpace(param)
+			eq := strings.IndexByte(param, '=')
+			if eq == -1 || !strings.EqualFold(strings.TrimSpace(param[:eq]), "rel") {
+				continue
+			}
+			value := strings.Trim(strings.TrimSpace(param[eq+1:]), `"`)
+			// rel may contain multiple space separated relation types, ie: rel="next last"
+			for _, rel := range strings.Fields(value) {
+				switch strings.ToLower(rel) {
+				case "next":
+					links.Next = link
+				case "prev", "previous":
+					links.Prev = link
+				case "first":
+					links.First = link
+				case "last":
+					links.Last = link
+				}
+			}
+		}
+	}
+
+	return links
+}
+
+func indexUnquoted(s string, c byte) int {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case c:
+			if !quoted {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// NewPageIterator returns a PageIterator for the paginated list at url. url must be a full URL; see the List*Pages
+// methods on the API structs for iterators over specific endpoints.
+func (apiInfo *APIInfo) NewPageIterator(url string, opts *ListOptions) *PageIterator {
+	return apiInfo.NewPageIteratorContext(context.Background(), url, opts)
+}
+
+// NewPageIteratorContext is like NewPageIterator but uses ctx for each page request.
+func (apiInfo *APIInfo) NewPageIteratorContext(ctx context.Context, url string, opts *ListOptions) *PageIterator {
+	it := &PageIterator{apiInfo: apiInfo, ctx: ctx}
+	if opts != nil {
+		it.opts = *opts
+	}
+
+	if it.opts.PerPage > 0 {
+		var err error
+		if url, err = setQueryParam(url, "per_page", strconv.Itoa(it.opts.PerPage)); err != nil {
+			it.err = err
+			return it
+		}
+	}
+
+	it.nextURL = url
+	return it
+}
+
+// Next requests the next page. It returns false when there are no more pages, a limit in ListOptions has been
+// reached, Stop has been called, or an error occurred; check Err after Next returns false.
+func (it *PageIterator) Next() bool {
+	it.closeResponse()
+
+	if it.done || it.err != nil || it.nextURL == "" {
+		return false
+	}
+	if (it.opts.MaxPages > 0 && it.pages >= it.opts.MaxPages) ||
+		(it.opts.MaxItems > 0 && it.items >= it.opts.MaxItems) {
+		it.done = true
+		return false
+	}
+
+	if it.rateResource != "" {
+		if err := it.apiInfo.waitForRate(it.ctx, it.rateResource); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	resp, err := it.apiInfo.doHTTPRequest(it.ctx, "GET", it.nextURL, nil, it.accept)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.resp = resp
+	it.links = ParseLinkHeader(resp.Header.Get("Link"))
+	it.nextURL = it.links.Next
+	it.pages++
+
+	return true
+}
+
+// Decode decodes the current page into v, which should be a pointer to a slice, or to a search result for SearchAPI
+// iterators. If ListOptions.MaxItems is set the slice is truncated so the total number of items decoded does not
+// exceed it.
+func (it *PageIterator) Decode(v interface{}) error {
+	if it.resp == nil {
+		return ErrPageNotRead
+	}
+	defer it.closeResponse()
+
+	if err := json.NewDecoder(it.resp.Body).Decode(v); err != nil {
+		it.err = err
+		return err
+	}
+
+	slice, ok := pageItems(v)
+	if !ok {
+		return nil
+	}
+
+	n := slice.Len()
+	if it.opts.MaxItems > 0 && it.items+n > it.opts.MaxItems {
+		n = it.opts.MaxItems - it.items
+		slice.Set(slice.Slice(0, n))
+	}
+	it.items += n
+
+	// an empty page means there's nothing left, regardless of what the Link header says
+	if n == 0 {
+		it.done = true
+	}
+
+	return nil
+}
+
+// pageItems returns the slice of items decoded into v, which is either a pointer to a slice or a pointer to a search
+// result, whose items are wrapped in an object.
+func pageItems(v interface{}) (reflect.Value, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr {
+		return reflect.Value{}, false
+	}
+	if rv.Elem().Kind() == reflect.Slice {
+		return rv.Elem(), true
+	}
+	if _, ok := v.(interface{ searchResult() *SearchResult }); ok {
+		return rv.Elem().FieldByName("Items"), true
+	}
+	return reflect.Value{}, false
+}
+
+// All reads every remaining page and appends the items to v, which must be a pointer to a slice.
+func (it *PageIterator) All(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return errors.New("PageIterator.All: v must be a pointer to a slice")
+	}
+	slice := rv.Elem()
+
+	for it.Next() {
+		page := reflect.New(slice.Type())
+		if err := it.Decode(page.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.AppendSlice(slice, page.Elem()))
+	}
+
+	return it.Err()
+}
+
+// Stop ends the iteration early. Subsequent calls to Next return false.
+func (it *PageIterator) Stop() {
+	it.closeResponse()
+	it.done = true
+}
+
+// Err returns the first error encountered by the iterator.
+func (it *PageIterator) Err() error {
+	return it.err
+}
+
+// Links returns the links parsed from the current page's "Link" header.
+func (it *PageIterator) Links() Links {
+	return it.links
+}
+
+// Response returns the *http.Response for the current page. The body is owned by the iterator; use Decode to
+// read it.
+func (it *PageIterator) Response() *http.Response {
+	return it.resp
+}
+
+// Pages returns the number of pages requested so far.
+func (it *PageIterator) Pages() int {
+	return it.pages
+}
+
+// Items returns the number of items decoded so far.
+func (it *PageIterator) Items() int {
+	return it.items
+}
+
+func (it *PageIterator) closeResponse() {
+	if it.resp != nil {
+		it.resp.Body.Close()
+		it.resp = nil
+	}
+}
+
+func setQueryParam(url, key, value string) (string, error) {
+	u, err := neturl.Parse(url)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set(key, value)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,179 @@
+package ghapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestParseLinkHeader(t *testing.T) {
+	header := `<https://api.github.com/resource?page=2>; rel="next", ` +
+		`<https://api.github.com/resource?page=5>; rel="last", ` +
+		`<https://api.github.com/resource?page=1>; rel="first", ` +
+		`<https://api.github.com/resource?page=0&q=a,b>; rel="prev"`
+
+	links := ParseLinkHeader(header)
+
+	expect(t, "https://api.github.com/resource?page=2", links.Next, "links.Next")
+	expect(t, "https://api.github.com/resource?page=5", links.Last, "links.Last")
+	expect(t, "https://api.github.com/resource?page=1", links.First, "links.First")
+	expect(t, "https://api.github.com/resource?page=0&q=a,b", links.Prev, "links.Prev")
+}
+
+func TestParseLinkHeader_Empty(t *testing.T) {
+	links := ParseLinkHeader("")
+
+	expect(t, Links{}, links, "links")
+}
+
+func TestParseLinkHeader_MultipleRelations(t *testing.T) {
+	links := ParseLinkHeader(`<https://api.github.com/resource?page=2>; rel="next last"`)
+
+	expect(t, "https://api.github.com/resource?page=2", links.Next, "links.Next")
+	expect(t, "https://api.github.com/resource?page=2", links.Last, "links.Last")
+	expect(t, "", links.Prev, "links.Prev")
+}
+
+// makePagedTestServer serves pages of integers from "/items", three items per page, with a "Link" header
+// pointing to the next page. It returns a pointer to the number of requests made.
+func makePagedTestServer(t *testing.T, totalPages int) (*httptest.Server, GitHubAPI, *int) {
+	requests := 0
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/items" {
+			w.WriteHeader(404)
+			return
+		}
+		page := 1
+		if p := r.URL.Query().Get("page"); p != "" {
+			var err error
+			if page, err = strconv.Atoi(p); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if page < totalPages {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=%d>; rel="next", <%s/items?page=%d>; rel="last"`,
+				ts.URL, page+1, ts.URL, totalPages))
+		} else {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=1>; rel="first", <%s/items?page=%d>; rel="prev"`,
+				ts.URL, ts.URL, page-1))
+		}
+		n := (page - 1) * 3
+		if _, err := w.Write([]byte(fmt.Sprintf("[%d,%d,%d]", n+1, n+2, n+3))); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken)
+	return ts, api, &requests
+}
+
+func TestPageIterator_All(t *testing.T) {
+	ts, api, requests := makePagedTestServer(t, 3)
+	defer ts.Close()
+
+	var items []int
+	err := api.NewPageIterator(ts.URL+"/items", nil).All(&items)
+
+	expectNil(t, err, "err")
+	expect(t, 9, len(items), "len(items)")
+	expect(t, 9, items[8], "items[8]")
+	// no extra request after the last page
+	expect(t, 3, *requests, "requests")
+}
+
+func TestPageIterator_MaxPages(t *testing.T) {
+	ts, api, requests := makePagedTestServer(t, 3)
+	defer ts.Close()
+
+	var items []int
+	err := api.NewPageIterator(ts.URL+"/items", &ListOptions{MaxPages: 2}).All(&items)
+
+	expectNil(t, err, "err")
+	expect(t, 6, len(items), "len(items)")
+	expect(t, 2, *requests, "requests")
+}
+
+func TestPageIterator_MaxItems(t *testing.T) {
+	ts, api, requests := makePagedTestServer(t, 3)
+	defer ts.Close()
+
+	var items []int
+	it := api.NewPageIterator(ts.URL+"/items", &ListOptions{MaxItems: 4})
+	err := it.All(&items)
+
+	expectNil(t, err, "err")
+	expect(t, 4, len(items), "len(items)")
+	expect(t, 4, it.Items(), "it.Items()")
+	expect(t, 2, *requests, "requests")
+}
+
+func TestPageIterator_Stop(t *testing.T) {
+	ts, api, requests := makePagedTestServer(t, 3)
+	defer ts.Close()
+
+	it := api.NewPageIterator(ts.URL+"/items", nil)
+	for it.Next() {
+		var items []int
+		if err := it.Decode(&items); err != nil {
+			t.Fatal(err)
+		}
+		it.Stop()
+	}
+
+	expectNil(t, it.Err(), "it.Err()")
+	expect(t, 1, it.Pages(), "it.Pages()")
+	expect(t, 1, *requests, "requests")
+}
+
+func TestPageIterator_PerPage(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "50", r.URL.Query().Get("per_page"), "per_page")
+		expect(t, "all", r.URL.Query().Get("state"), "state")
+		if _, err := w.Write([]byte("[]")); err != nil {
+			t.Fatal(err)
+		}
+	})
+	defer ts.Close()
+
+	var pullRequests []PullRequestResponse
+	err := api.PullRequest.ListPullRequestsPages("all", &ListOptions{PerPage: 50}).All(&pullRequests)
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, 0, len(pullRequests), "len(pullRequests)")
+}
+
+func TestPageIterator_ReturnsErrOnHttpErr(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	})
+	defer ts.Close()
+
+	var labels []IssueLabel
+	err := api.Repository.GetLabelsPages(nil).All(&labels)
+	waitSignal(t, signal)
+
+	expectErrHTTPError500(t, err)
+}
+
+func TestRepositoryAPI_GetCommits_RequestsPage(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "/repos/test_owner/test_repository/commits", r.URL.Path, "r.URL.Path")
+		expect(t, "3", r.URL.Query().Get("page"), "page")
+		w.Header().Set("Link", `<http://example.org/commits?page=4>; rel="next"`)
+		if _, err := w.Write([]byte(`[{"sha":"abc"}]`)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	defer ts.Close()
+
+	commits, err := api.Repository.GetCommits(3)
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, 1, len(commits), "len(commits)")
+	expect(t, "abc", commits[0].SHA, "commits[0].SHA")
+}
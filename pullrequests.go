@@ -1,6 +1,7 @@
 package ghapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -51,47 +52,31 @@ type MergeRequestResponse struct {
 
 // PullRequestResponse contains information about a pull request.
 type PullRequestResponse struct {
-	URL            string     `json:"url"`
-	ID             int        `json:"id"`
-	HTMLURL        string     `json:"html_url"`
-	DiffURL        string     `json:"diff_url"`
-	PatchURL       string     `json:"patch_url"`
-	IssueURL       string     `json:"issue_url"`
-	Number         int        `json:"number"`
-	State          string     `json:"state"`
-	Locked         bool       `json:"locked"`
-	Title          string     `json:"title"`
-	User           User       `json:"user"`
-	Body           string     `json:"body"`
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
-	ClosedAt       *time.Time `json:"closed_at"`
-	MergedAt       *time.Time `json:"merged_at"`
-	MergeCommitSHA string     `json:"merge_commit_sha"`
-	Assignee       *User      `json:"assignee"`
-	Assignees      []User     `json:"assignees"`
-	Milestone      *struct {
-		URL          string     `json:"url"`
-		HTMLURL      string     `json:"html_url"`
-		LabelsURL    string     `json:"labels_url"`
-		ID           int        `json:"id"`
-		Number       int        `json:"number"`
-		State        string     `json:"state"`
-		Title        string     `json:"title"`
-		Description  string     `json:"description"`
-		Creator      User       `json:"creator"`
-		OpenIssues   int        `json:"open_issues"`
-		ClosedIssues int        `json:"closed_issues"`
-		CreatedAt    time.Time  `json:"created_at"`
-		UpdatedAt    time.Time  `json:"updated_at"`
-		ClosedAt     *time.Time `json:"closed_at"`
-		DueOn        *time.Time `json:"due_on"`
-	} `json:"milestone"`
-	CommitsURL        string `json:"commits_url"`
-	ReviewCommentsURL string `json:"review_comments_url"`
-	ReviewCommentURL  string `json:"review_comment_url"`
-	CommentsURL       string `json:"comments_url"`
-	StatusesURL       string `json:"statuses_url"`
+	URL               string     `json:"url"`
+	ID                int        `json:"id"`
+	HTMLURL           string     `json:"html_url"`
+	DiffURL           string     `json:"diff_url"`
+	PatchURL          string     `json:"patch_url"`
+	IssueURL          string     `json:"issue_url"`
+	Number            int        `json:"number"`
+	State             string     `json:"state"`
+	Locked            bool       `json:"locked"`
+	Title             string     `json:"title"`
+	User              User       `json:"user"`
+	Body              string     `json:"body"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	ClosedAt          *time.Time `json:"closed_at"`
+	MergedAt          *time.Time `json:"merged_at"`
+	MergeCommitSHA    string     `json:"merge_commit_sha"`
+	Assignee          *User      `json:"assignee"`
+	Assignees         []User     `json:"assignees"`
+	Milestone         *Milestone `json:"milestone"`
+	CommitsURL        string     `json:"commits_url"`
+	ReviewCommentsURL string     `json:"review_comments_url"`
+	ReviewCommentURL  string     `json:"review_comment_url"`
+	CommentsURL       string     `json:"comments_url"`
+	StatusesURL       string     `json:"statuses_url"`
 	Head              struct {
 		Label string `json:"label"`
 		Ref   string `json:"ref"`
@@ -284,45 +269,29 @@ type PullRequestResponse struct {
 
 // CreatePullRequestResponse is returned by PullRequestsAPI.Create.
 type CreatePullRequestResponse struct {
-	ID                int    `json:"id"`
-	URL               string `json:"url"`
-	HTMLURL           string `json:"html_url"`
-	DiffURL           string `json:"diff_url"`
-	PatchURL          string `json:"patch_url"`
-	IssueURL          string `json:"issue_url"`
-	CommitsURL        string `json:"commits_url"`
-	ReviewCommentsURL string `json:"review_comments_url"`
-	ReviewCommentURL  string `json:"review_comment_url"`
-	CommentsURL       string `json:"comments_url"`
-	StatusesURL       string `json:"statuses_url"`
-	Number            int    `json:"number"`
-	State             string `json:"state"`
-	Title             string `json:"title"`
-	Body              string `json:"body"`
-	Assignee          *User  `json:"assignee"`
-	Milestone         struct {
-		URL          string    `json:"url"`
-		HTMLURL      string    `json:"html_url"`
-		LabelsURL    string    `json:"labels_url"`
-		ID           int       `json:"id"`
-		Number       int       `json:"number"`
-		State        string    `json:"state"`
-		Title        string    `json:"title"`
-		Description  string    `json:"description"`
-		Creator      User      `json:"creator"`
-		OpenIssues   int       `json:"open_issues"`
-		ClosedIssues int       `json:"closed_issues"`
-		CreatedAt    time.Time `json:"created_at"`
-		UpdatedAt    time.Time `json:"updated_at"`
-		ClosedAt     time.Time `json:"closed_at"`
-		DueOn        time.Time `json:"due_on"`
-	} `json:"milestone"`
-	Locked    bool      `json:"locked"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	ClosedAt  time.Time `json:"closed_at"`
-	MergedAt  time.Time `json:"merged_at"`
-	Head      struct {
+	ID                int       `json:"id"`
+	URL               string    `json:"url"`
+	HTMLURL           string    `json:"html_url"`
+	DiffURL           string    `json:"diff_url"`
+	PatchURL          string    `json:"patch_url"`
+	IssueURL          string    `json:"issue_url"`
+	CommitsURL        string    `json:"commits_url"`
+	ReviewCommentsURL string    `json:"review_comments_url"`
+	ReviewCommentURL  string    `json:"review_comment_url"`
+	CommentsURL       string    `json:"comments_url"`
+	StatusesURL       string    `json:"statuses_url"`
+	Number            int       `json:"number"`
+	State             string    `json:"state"`
+	Title             string    `json:"title"`
+	Body              string    `json:"body"`
+	Assignee          *User     `json:"assignee"`
+	Milestone         Milestone `json:"milestone"`
+	Locked            bool      `json:"locked"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	ClosedAt          time.Time `json:"closed_at"`
+	MergedAt          time.Time `json:"merged_at"`
+	Head              struct {
 		Label string `json:"label"`
 		Ref   string `json:"ref"`
 		SHA   string `json:"sha"`
@@ -544,38 +513,42 @@ type PullRequestCommit struct {
 // ListPullRequests lists pull requests for the repository.
 // state is either "open", "closed", or "all".
 func (api *PullRequestsAPI) ListPullRequests(state string) ([]PullRequestResponse, error) {
+	return api.ListPullRequestsContext(context.Background(), state)
+}
+
+// ListPullRequestsContext is like ListPullRequests but uses the provided context.
+func (api *PullRequestsAPI) ListPullRequestsContext(ctx context.Context, state string) ([]PullRequestResponse, error) {
 	var allPullRequests []PullRequestResponse
-	for page := 1; ; page++ {
-		url := api.getURL(fmt.Sprintf("/repos/:owner/:repo/pulls?state=%s&page=%d", state, page))
-
-		resp, err := api.httpGet(url)
-		//resp.Header["Link"] // TODO (judwhite), get next page until last
-		//<url>; rel="last", <url>; rel="first", <url>; rel="prev", <url>; rel="next"
-		if err != nil {
-			return nil, err
-		}
-
-		pullRequests := []PullRequestResponse{}
-		if err = json.NewDecoder(resp.Body).Decode(&pullRequests); err != nil {
-			resp.Body.Close()
-			return nil, err
-		}
-		resp.Body.Close()
-
-		allPullRequests = append(allPullRequests, pullRequests...)
-		if len(pullRequests) == 0 || resp.Header.Get("Link") == "" {
-			break
-		}
+	if err := api.ListPullRequestsPagesContext(ctx, state, nil).All(&allPullRequests); err != nil {
+		return nil, err
 	}
 
 	return allPullRequests, nil
 }
 
+// ListPullRequestsPages returns a PageIterator over the repository's pull requests. Each page decodes to
+// []PullRequestResponse.
+// state is either "open", "closed", or "all".
+func (api *PullRequestsAPI) ListPullRequestsPages(state string, opts *ListOptions) *PageIterator {
+	return api.ListPullRequestsPagesContext(context.Background(), state, opts)
+}
+
+// ListPullRequestsPagesContext is like ListPullRequestsPages but uses ctx for each page request.
+func (api *PullRequestsAPI) ListPullRequestsPagesContext(ctx context.Context, state string, opts *ListOptions) *PageIterator {
+	url := api.getURL(fmt.Sprintf("/repos/:owner/:repo/pulls?state=%s", state))
+	return api.NewPageIteratorContext(ctx, url, opts)
+}
+
 // GetPullRequest get a pull request by PR number.
 func (api *PullRequestsAPI) GetPullRequest(pullRequestNumber int) (*PullRequestResponse, error) {
+	return api.GetPullRequestContext(context.Background(), pullRequestNumber)
+}
+
+// GetPullRequestContext is like GetPullRequest but uses the provided context.
+func (api *PullRequestsAPI) GetPullRequestContext(ctx context.Context, pullRequestNumber int) (*PullRequestResponse, error) {
 	url := api.getURL("/repos/:owner/:repo/pulls/" + strconv.Itoa(pullRequestNumber))
 
-	resp, err := api.httpGet(url)
+	resp, err := api.httpGet(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -593,6 +566,11 @@ func (api *PullRequestsAPI) GetPullRequest(pullRequestNumber int) (*PullRequestR
 
 // MergePullRequest merges a pull request using the specified method.
 func (api *PullRequestsAPI) MergePullRequest(pullRequestNumber int, method MergeMethod) (*MergeRequestResponse, error) {
+	return api.MergePullRequestContext(context.Background(), pullRequestNumber, method)
+}
+
+// MergePullRequestContext is like MergePullRequest but uses the provided context.
+func (api *PullRequestsAPI) MergePullRequestContext(ctx context.Context, pullRequestNumber int, method MergeMethod) (*MergeRequestResponse, error) {
 	url := api.getURL("/repos/:owner/:repo/pulls/" + strconv.Itoa(pullRequestNumber) + "/merge")
 	body := struct {
 		MergeMethod string `json:"merge_method"`
@@ -601,7 +579,7 @@ func (api *PullRequestsAPI) MergePullRequest(pullRequestNumber int, method Merge
 	if err != nil {
 		return nil, err
 	}
-	resp, err := api.httpPut(url, string(b))
+	resp, err := api.httpPut(ctx, url, string(b))
 	if err != nil {
 		return nil, err
 	}
@@ -618,36 +596,39 @@ func (api *PullRequestsAPI) MergePullRequest(pullRequestNumber int, method Merge
 
 // GetCommits gets all commits for a pull request by PR number.
 func (api *PullRequestsAPI) GetCommits(pullRequestNumber int) ([]PullRequestCommit, error) {
+	return api.GetCommitsContext(context.Background(), pullRequestNumber)
+}
+
+// GetCommitsContext is like GetCommits but uses the provided context.
+func (api *PullRequestsAPI) GetCommitsContext(ctx context.Context, pullRequestNumber int) ([]PullRequestCommit, error) {
 	var allCommits []PullRequestCommit
-	for page := 1; ; page++ {
-		url := api.getURL(fmt.Sprintf("/repos/:owner/:repo/pulls/%d/commits?page=%d", pullRequestNumber, page))
-
-		resp, err := api.httpGet(url)
-		//resp.Header["Link"] // TODO (judwhite), get next page until last
-		//<url>; rel="last", <url>; rel="first", <url>; rel="prev", <url>; rel="next"
-		if err != nil {
-			return nil, err
-		}
-
-		commits := []PullRequestCommit{}
-		if err = json.NewDecoder(resp.Body).Decode(&commits); err != nil {
-			resp.Body.Close()
-			return nil, err
-		}
-		resp.Body.Close()
-
-		allCommits = append(allCommits, commits...)
-		if len(commits) == 0 || resp.Header.Get("Link") == "" {
-			break
-		}
+	if err := api.GetCommitsPagesContext(ctx, pullRequestNumber, nil).All(&allCommits); err != nil {
+		return nil, err
 	}
 
 	return allCommits, nil
 }
 
+// GetCommitsPages returns a PageIterator over the commits of a pull request by PR number. Each page decodes to
+// []PullRequestCommit.
+func (api *PullRequestsAPI) GetCommitsPages(pullRequestNumber int, opts *ListOptions) *PageIterator {
+	return api.GetCommitsPagesContext(context.Background(), pullRequestNumber, opts)
+}
+
+// GetCommitsPagesContext is like GetCommitsPages but uses ctx for each page request.
+func (api *PullRequestsAPI) GetCommitsPagesContext(ctx context.Context, pullRequestNumber int, opts *ListOptions) *PageIterator {
+	url := api.getURL(fmt.Sprintf("/repos/:owner/:repo/pulls/%d/commits", pullRequestNumber))
+	return api.NewPageIteratorContext(ctx, url, opts)
+}
+
 // Create creates a Pull Request using head (owner:branch) targeting the base (target branch).
 // See https://developer.github.com/v3/pulls/#create-a-pull-request
 func (api *PullRequestsAPI) Create(head, base, title, body string, maintainerCanModify bool) (*CreatePullRequestResponse, error) {
+	return api.CreateContext(context.Background(), head, base, title, body, maintainerCanModify)
+}
+
+// CreateContext is like Create but uses the provided context.
+func (api *PullRequestsAPI) CreateContext(ctx context.Context, head, base, title, body string, maintainerCanModify bool) (*CreatePullRequestResponse, error) {
 	post := struct {
 		Head                string `json:"head"`
 		Base                string `json:"base"`
@@ -669,7 +650,7 @@ func (api *PullRequestsAPI) Create(head, base, title, body string, maintainerCan
 		return nil, err
 	}
 
-	resp, err := api.httpPost(url, string(b))
+	resp, err := api.httpPost(ctx, url, string(b))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,314 @@
+package ghapi
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultQueueWorkers is the number of workers used by an EventQueue by default.
+const DefaultQueueWorkers = 4
+
+// DefaultMaxPending is the number of events an EventQueue holds by default before Enqueue returns ErrQueueFull.
+const DefaultMaxPending = 1000
+
+// EventQueue processes webhook events in the background, so a webhook endpoint can acknowledge an event within
+// GitHub's 10 second timeout and do slower work, such as merging a pull request, afterwards.
+//
+// Events for the same repository are processed one at a time in the order they were enqueued; events for different
+// repositories are processed concurrently by up to Workers goroutines. A failed event is retried according to Retry
+// before the next event for its repository is started.
+//
+// Set the exported fields before calling Start.
+type EventQueue struct {
+	// Workers is the number of events processed concurrently. Defaults to DefaultQueueWorkers.
+	Workers int
+	// MaxPending is the number of events waiting to be processed before Enqueue returns ErrQueueFull. Defaults to
+	// DefaultMaxPending.
+	MaxPending int
+	// Retry controls retries of events whose handler returns an error. MaxAttempts, MinBackoff and MaxBackoff are
+	// used. The zero value doesn't retry.
+	Retry RetryPolicy
+	// OrderKey returns the key events are ordered by. Defaults to the repository's full name, or the organization's
+	// login for organization events. Events with an empty key aren't ordered.
+	OrderKey func(delivery *Delivery) string
+	// OnFailure, if set, is called with events which failed every attempt, for example to record them for replay or
+	// to call DeliveryStore.Forget so GitHub can redeliver them. Events enqueued by a WebhookServer are forgotten from
+	// its Deliveries before OnFailure is called.
+	OnFailure func(delivery *Delivery, err error)
+	// Logger logs failed attempts. If nil, nothing is logged.
+	Logger Logger
+
+	handler func(ctx context.Context, delivery *Delivery) error
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mtx       sync.Mutex
+	cond      *sync.Cond
+	keys      map[string]*list.List
+	scheduled map[string]bool
+	ready     *list.List
+	pending   int
+	inFlight  int
+	closed    bool
+	seq       uint64
+
+	processed int64
+	failed    int64
+	retries   int64
+}
+
+// QueueStats contains EventQueue metrics. See EventQueue.Stats.
+type QueueStats struct {
+	// Pending is the number of events waiting to be processed, the queue depth.
+	Pending int
+	// InFlight is the number of events being processed.
+	InFlight int
+	// Processed is the number of events handled successfully.
+	Processed int64
+	// Failed is the number of events which failed every attempt.
+	Failed int64
+	// Retries is the number of retried attempts.
+	Retries int64
+}
+
+type queuedDelivery struct {
+	key      string
+	delivery *Delivery
+}
+
+// NewEventQueue returns an EventQueue which processes events with handler. Call Start to start the workers.
+func NewEventQueue(handler func(ctx context.Context, delivery *Delivery) error) *EventQueue {
+	q := &EventQueue{
+		handler:   handler,
+		keys:      make(map[string]*list.List),
+		scheduled: make(map[string]bool),
+		ready:     list.New(),
+	}
+	q.cond = sync.NewCond(&q.mtx)
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+	return q
+}
+
+// Start starts the workers.
+func (q *EventQueue) Start() {
+	workers := q.Workers
+	if workers <= 0 {
+		workers = DefaultQueueWorkers
+	}
+
+	q.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go q.work()
+	}
+}
+
+// Enqueue adds delivery to the queue and returns without waiting for it to be processed. ErrQueueFull is returned if
+// MaxPending events are waiting, and ErrQueueClosed after Shutdown has been called.
+func (q *EventQueue) Enqueue(delivery *Delivery) error {
+	key := q.orderKey(delivery)
+
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+	maxPending := q.MaxPending
+	if maxPending <= 0 {
+		maxPending = DefaultMaxPending
+	}
+	if q.pending >= maxPending {
+		return ErrQueueFull
+	}
+
+	if key == "" {
+		// unordered events get a key of their own
+		q.seq++
+		key = fmt.Sprintf("\x00%d", q.seq)
+	}
+
+	pending, ok := q.keys[key]
+	if !ok {
+		pending = list.New()
+		q.keys[key] = pending
+	}
+	pending.PushBack(delivery)
+	q.pending++
+
+	if !q.scheduled[key] {
+		q.scheduled[key] = true
+		q.ready.PushBack(key)
+		q.cond.Signal()
+	}
+	return nil
+}
+
+// Shutdown stops accepting events and waits for the queued and in-flight events to be processed. If ctx is done
+// first, the context passed to handlers is canceled, retries are abandoned, and ctx's error is returned once the
+// workers exit.
+func (q *EventQueue) Shutdown(ctx context.Context) error {
+	q.mtx.Lock()
+	q.closed = true
+	q.cond.Broadcast()
+	q.mtx.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Stats returns the queue's metrics.
+func (q *EventQueue) Stats() QueueStats {
+	q.mtx.Lock()
+	pending, inFlight := q.pending, q.inFlight
+	q.mtx.Unlock()
+
+	return QueueStats{
+		Pending:   pending,
+		InFlight:  inFlight,
+		Processed: atomic.LoadInt64(&q.processed),
+		Failed:    atomic.LoadInt64(&q.failed),
+		Retries:   atomic.LoadInt64(&q.retries),
+	}
+}
+
+func (q *EventQueue) work() {
+	defer q.wg.Done()
+
+	for {
+		next, ok := q.next()
+		if !ok {
+			return
+		}
+		q.process(next.delivery)
+		q.finish(next.key)
+	}
+}
+
+// next waits for an event whose key isn't being processed by another worker. It returns false when the queue is
+// closed and empty.
+func (q *EventQueue) next() (queuedDelivery, bool) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	for q.ready.Len() == 0 {
+		if q.closed {
+			return queuedDelivery{}, false
+		}
+		q.cond.Wait()
+	}
+
+	key := q.ready.Remove(q.ready.Front()).(string)
+	pending := q.keys[key]
+	delivery := pending.Remove(pending.Front()).(*Delivery)
+	q.pending--
+	q.inFlight++
+
+	return queuedDelivery{key: key, delivery: delivery}, true
+}
+
+// finish reschedules key if more of its events are waiting.
+func (q *EventQueue) finish(key string) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	q.inFlight--
+	if q.keys[key].Len() != 0 {
+		q.ready.PushBack(key)
+		q.cond.Signal()
+		return
+	}
+	delete(q.keys, key)
+	delete(q.scheduled, key)
+}
+
+func (q *EventQueue) process(delivery *Delivery) {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = q.handle(delivery); err == nil {
+			atomic.AddInt64(&q.processed, 1)
+			return
+		}
+		if q.Logger != nil {
+			q.Logger.Printf("ghapi: queued %s event %s failed attempt %d: %v", delivery.EventType, delivery.ID, attempt, err)
+		}
+
+		if attempt >= q.Retry.MaxAttempts || q.ctx.Err() != nil {
+			break
+		}
+		atomic.AddInt64(&q.retries, 1)
+		if sleepContext(q.ctx, q.Retry.exponential(attempt)) != nil {
+			break
+		}
+	}
+
+	atomic.AddInt64(&q.failed, 1)
+	if delivery.failed != nil {
+		delivery.failed(err)
+	}
+	if q.OnFailure != nil {
+		q.OnFailure(delivery, err)
+	}
+}
+
+// handle calls the handler, returning a panic as an error so one bad event doesn't stop the worker.
+func (q *EventQueue) handle(delivery *Delivery) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return q.handler(q.ctx, delivery)
+}
+
+func (q *EventQueue) orderKey(delivery *Delivery) string {
+	if q.OrderKey != nil {
+		return q.OrderKey(delivery)
+	}
+
+	repo, org := payloadOwner(delivery.Body)
+	if repo != "" {
+		return repo
+	}
+	return org
+}
+
+// payloadOwner returns the repository's full name and organization's login from an event payload, if present.
+func payloadOwner(body []byte) (repo, org string) {
+	var payload struct {
+		Repository *struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+		Organization *struct {
+			Login string `json:"login"`
+		} `json:"organization"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", ""
+	}
+	if payload.Repository != nil {
+		repo = payload.Repository.FullName
+	}
+	if payload.Organization != nil {
+		org = payload.Organization.Login
+	}
+	return repo, org
+}
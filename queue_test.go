@@ -0,0 +1,213 @@
+package ghapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func makeQueuedDelivery(repo string, n int) *Delivery {
+	return &Delivery{
+		ID:        fmt.Sprintf("%s-%d", repo, n),
+		EventType: PushEventType,
+		Body:      []byte(fmt.Sprintf(`{"ref":"%d","repository":{"full_name":%q}}`, n, repo)),
+	}
+}
+
+func TestEventQueue_OrdersByRepository(t *testing.T) {
+	var mtx sync.Mutex
+	order := make(map[string][]string)
+	active := make(map[string]int)
+	var overlapped bool
+	var maxActive, totalActive int
+
+	q := NewEventQueue(func(ctx context.Context, delivery *Delivery) error {
+		payload, err := ParsePayload(delivery.EventType, delivery.Body)
+		if err != nil {
+			return err
+		}
+		repo := payload.(*PushEventPayload).Repository.FullName
+
+		mtx.Lock()
+		active[repo]++
+		totalActive++
+		if active[repo] > 1 {
+			overlapped = true
+		}
+		if totalActive > maxActive {
+			maxActive = totalActive
+		}
+		mtx.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mtx.Lock()
+		active[repo]--
+		totalActive--
+		order[repo] = append(order[repo], delivery.ID)
+		mtx.Unlock()
+		return nil
+	})
+	q.Workers = 3
+
+	repos := []string{"octocat/a", "octocat/b", "octocat/c"}
+	for i := 0; i < 10; i++ {
+		for _, repo := range repos {
+			expectNil(t, q.Enqueue(makeQueuedDelivery(repo, i)), "Enqueue")
+		}
+	}
+	expect(t, 30, q.Stats().Pending, "Stats().Pending")
+
+	q.Start()
+	expectNil(t, q.Shutdown(context.Background()), "Shutdown")
+
+	expect(t, false, overlapped, "events for one repository overlapped")
+	if maxActive < 2 {
+		t.Fatalf("events for different repositories weren't processed concurrently, max %d", maxActive)
+	}
+	for _, repo := range repos {
+		for i, id := range order[repo] {
+			expect(t, fmt.Sprintf("%s-%d", repo, i), id, repo)
+		}
+	}
+
+	stats := q.Stats()
+	expect(t, 0, stats.Pending, "stats.Pending")
+	expect(t, 0, stats.InFlight, "stats.InFlight")
+	expect(t, int64(30), stats.Processed, "stats.Processed")
+}
+
+func TestEventQueue_Retries(t *testing.T) {
+	var attempts int
+	q := NewEventQueue(func(ctx context.Context, delivery *Delivery) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	})
+	q.Workers = 1
+	q.Retry = RetryPolicy{MaxAttempts: 3}
+
+	expectNil(t, q.Enqueue(makeQueuedDelivery("octocat/a", 0)), "Enqueue")
+	q.Start()
+	expectNil(t, q.Shutdown(context.Background()), "Shutdown")
+
+	stats := q.Stats()
+	expect(t, 3, attempts, "attempts")
+	expect(t, int64(2), stats.Retries, "stats.Retries")
+	expect(t, int64(1), stats.Processed, "stats.Processed")
+	expect(t, int64(0), stats.Failed, "stats.Failed")
+}
+
+func TestEventQueue_OnFailure(t *testing.T) {
+	q := NewEventQueue(func(ctx context.Context, delivery *Delivery) error {
+		if delivery.ID == "octocat/a-1" {
+			panic("bad event")
+		}
+		return errors.New("permanent failure")
+	})
+	q.Retry = RetryPolicy{MaxAttempts: 2}
+
+	var mtx sync.Mutex
+	failures := make(map[string]string)
+	q.OnFailure = func(delivery *Delivery, err error) {
+		mtx.Lock()
+		failures[delivery.ID] = err.Error()
+		mtx.Unlock()
+	}
+
+	expectNil(t, q.Enqueue(makeQueuedDelivery("octocat/a", 0)), "Enqueue")
+	expectNil(t, q.Enqueue(makeQueuedDelivery("octocat/a", 1)), "Enqueue")
+	q.Start()
+	expectNil(t, q.Shutdown(context.Background()), "Shutdown")
+
+	expect(t, "permanent failure", failures["octocat/a-0"], "failures[0]")
+	expect(t, "panic: bad event", failures["octocat/a-1"], "failures[1]")
+	expect(t, int64(2), q.Stats().Failed, "Stats().Failed")
+}
+
+func TestEventQueue_Full(t *testing.T) {
+	q := NewEventQueue(func(ctx context.Context, delivery *Delivery) error { return nil })
+	q.MaxPending = 2
+
+	expectNil(t, q.Enqueue(makeQueuedDelivery("octocat/a", 0)), "Enqueue")
+	expectNil(t, q.Enqueue(makeQueuedDelivery("octocat/b", 0)), "Enqueue")
+	expect(t, ErrQueueFull, q.Enqueue(makeQueuedDelivery("octocat/c", 0)), "Enqueue when full")
+
+	q.Start()
+	expectNil(t, q.Shutdown(context.Background()), "Shutdown")
+	expect(t, ErrQueueClosed, q.Enqueue(makeQueuedDelivery("octocat/a", 1)), "Enqueue after Shutdown")
+}
+
+func TestEventQueue_ShutdownTimeout(t *testing.T) {
+	started := make(chan struct{})
+	var handlerErr error
+	q := NewEventQueue(func(ctx context.Context, delivery *Delivery) error {
+		close(started)
+		<-ctx.Done()
+		handlerErr = ctx.Err()
+		return handlerErr
+	})
+	q.Start()
+
+	expectNil(t, q.Enqueue(makeQueuedDelivery("octocat/a", 0)), "Enqueue")
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	expect(t, context.DeadlineExceeded, q.Shutdown(ctx), "Shutdown")
+	expect(t, context.Canceled, handlerErr, "handlerErr")
+}
+
+func TestWebhookServer_QueueForgetsFailedDeliveries(t *testing.T) {
+	s := NewWebhookServer(webhookTestSecret)
+	s.Deliveries = NewMemoryDeliveryStore(0)
+	s.Queue = NewEventQueue(s.HandleDelivery)
+
+	var failures int
+	s.Queue.OnFailure = func(delivery *Delivery, err error) {
+		failures++
+	}
+	s.OnPush(func(ctx context.Context, payload *PushEventPayload) error {
+		return errors.New("database unavailable")
+	})
+
+	expect(t, http.StatusAccepted, serveWebhook(s, makeDeliveryRequest(t, testDeliveryID)).Code, "w.Code")
+	s.Queue.Start()
+	expectNil(t, s.Queue.Shutdown(context.Background()), "Shutdown")
+	expect(t, 1, failures, "failures")
+
+	seen, err := s.Deliveries.Seen(testDeliveryID)
+	expectNil(t, err, "err")
+	expect(t, false, seen, "seen after failing")
+}
+
+func TestWebhookServer_Queue(t *testing.T) {
+	s := NewWebhookServer(webhookTestSecret)
+	s.Queue = NewEventQueue(s.HandleDelivery)
+
+	var ref string
+	s.OnPush(func(ctx context.Context, payload *PushEventPayload) error {
+		ref = payload.Ref
+		return nil
+	})
+
+	w := serveWebhook(s, makeDeliveryRequest(t, testDeliveryID))
+	expect(t, http.StatusAccepted, w.Code, "w.Code")
+	expect(t, 1, s.Queue.Stats().Pending, "Stats().Pending")
+
+	s.Queue.Start()
+	expectNil(t, s.Queue.Shutdown(context.Background()), "Shutdown")
+	expect(t, "refs/heads/master", ref, "ref")
+
+	badJSON := makeWebhookRequest(t, PushEventType, `{"ref":`)
+	expect(t, http.StatusBadRequest, serveWebhook(s, badJSON).Code, "bad JSON")
+
+	closed := makeDeliveryRequest(t, testDeliveryID)
+	expect(t, http.StatusServiceUnavailable, serveWebhook(s, closed).Code, "queue closed")
+}
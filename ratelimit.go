@@ -0,0 +1,221 @@
+package ghapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// CoreRateLimitResource is the rate limit resource for most REST API calls.
+	CoreRateLimitResource = "core"
+	// SearchRateLimitResource is the rate limit resource for the Search API.
+	SearchRateLimitResource = "search"
+	// GraphQLRateLimitResource is the rate limit resource for the GraphQL API.
+	GraphQLRateLimitResource = "graphql"
+)
+
+// Rate contains rate limit information for a single resource, as returned by the "X-RateLimit-*" headers
+// and the /rate_limit endpoint.
+type Rate struct {
+	// Limit is the maximum number of requests permitted per hour.
+	Limit int
+	// Remaining is the number of requests remaining in the current window.
+	Remaining int
+	// Reset is the time the current window resets.
+	Reset time.Time
+}
+
+// UnmarshalJSON unmarshals a rate from the /rate_limit endpoint, where "reset" is in UTC epoch seconds.
+func (r *Rate) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		Limit     int   `json:"limit"`
+		Remaining int   `json:"remaining"`
+		Reset     int64 `json:"reset"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	r.Limit = raw.Limit
+	r.Remaining = raw.Remaining
+	r.Reset = time.Unix(raw.Reset, 0)
+	return nil
+}
+
+// RateLimits is returned by APIInfo.GetRateLimit.
+// See https://developer.github.com/v3/rate_limit/.
+type RateLimits struct {
+	Resources struct {
+		Core    Rate `json:"core"`
+		Search  Rate `json:"search"`
+		GraphQL Rate `json:"graphql"`
+	} `json:"resources"`
+}
+
+// rateLimitState holds the last seen rate limit for each resource. It's shared by every copy of an APIInfo
+// created by NewGitHubAPI.
+type rateLimitState struct {
+	mtx   sync.Mutex
+	rates map[string]Rate
+}
+
+// GetRateLimit returns the current rate limit status for each resource. Calling /rate_limit does not count
+// against the rate limit.
+func (apiInfo *APIInfo) GetRateLimit() (*RateLimits, error) {
+	return apiInfo.GetRateLimitContext(context.Background())
+}
+
+// GetRateLimitContext is like GetRateLimit but uses the provided context.
+func (apiInfo *APIInfo) GetRateLimitContext(ctx context.Context) (*RateLimits, error) {
+	url := apiInfo.addBaseURL("/rate_limit")
+
+	resp, err := apiInfo.httpGet(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var rateLimits RateLimits
+
+	j := json.NewDecoder(resp.Body)
+	if err = j.Decode(&rateLimits); err != nil {
+		return nil, err
+	}
+
+	apiInfo.setRate(CoreRateLimitResource, rateLimits.Resources.Core)
+	apiInfo.setRate(SearchRateLimitResource, rateLimits.Resources.Search)
+	apiInfo.setRate(GraphQLRateLimitResource, rateLimits.Resources.GraphQL)
+
+	return &rateLimits, nil
+}
+
+// LastRate returns the rate limit for resource seen on the most recent response. ok is false if no response has
+// reported a rate limit for resource. See CoreRateLimitResource and SearchRateLimitResource.
+func (apiInfo *APIInfo) LastRate(resource string) (rate Rate, ok bool) {
+	if apiInfo.rateLimits == nil {
+		return Rate{}, false
+	}
+
+	apiInfo.rateLimits.mtx.Lock()
+	defer apiInfo.rateLimits.mtx.Unlock()
+
+	rate, ok = apiInfo.rateLimits.rates[resource]
+	return rate, ok
+}
+
+func (apiInfo *APIInfo) setRate(resource string, rate Rate) {
+	if apiInfo.rateLimits == nil || rate.Limit == 0 {
+		return
+	}
+
+	apiInfo.rateLimits.mtx.Lock()
+	defer apiInfo.rateLimits.mtx.Unlock()
+
+	if apiInfo.rateLimits.rates == nil {
+		apiInfo.rateLimits.rates = make(map[string]Rate)
+	}
+	apiInfo.rateLimits.rates[resource] = rate
+}
+
+// recordRate records the rate limit reported in the response to req, and passes it on to the Authenticator if it's a
+// RateObserver.
+func (apiInfo *APIInfo) recordRate(req *http.Request, header http.Header) {
+	rate, resource, ok := parseRate(header)
+	if !ok {
+		return
+	}
+
+	apiInfo.setRate(resource, rate)
+	if observer, ok := apiInfo.authenticator().(RateObserver); ok {
+		observer.ObserveRate(req, resource, rate)
+	}
+}
+
+// parseRate parses the "X-RateLimit-*" headers. GitHub sends "X-RateLimit-Resource" to identify which limit applies;
+// when it's absent the core limit is assumed.
+func parseRate(header http.Header) (rate Rate, resource string, ok bool) {
+	limit := header.Get("X-RateLimit-Limit")
+	if limit == "" {
+		return Rate{}, "", false
+	}
+
+	var err error
+	if rate.Limit, err = strconv.Atoi(limit); err != nil {
+		return Rate{}, "", false
+	}
+	if rate.Remaining, err = strconv.Atoi(header.Get("X-RateLimit-Remaining")); err != nil {
+		return Rate{}, "", false
+	}
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rate.Reset = time.Unix(reset, 0)
+	}
+
+	resource = header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = CoreRateLimitResource
+	}
+
+	return rate, resource, true
+}
+
+// rateLimitError returns an *ErrRateLimited or *ErrSecondaryRateLimit if resp indicates a rate limit was exceeded,
+// otherwise it returns httpErr.
+func rateLimitError(resp *http.Response, httpErr *ErrHTTPError) error {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return httpErr
+	}
+
+	if rate, _, ok := parseRate(resp.Header); ok && rate.Remaining == 0 {
+		return &ErrRateLimited{ErrHTTPError: httpErr, Rate: rate}
+	}
+
+	retryAfter := resp.Header.Get("Retry-After")
+	message := strings.ToLower(httpErr.ResponseBody)
+	if retryAfter == "" && !strings.Contains(message, "secondary rate limit") && !strings.Contains(message, "abuse") {
+		return httpErr
+	}
+
+	// without a Retry-After header GitHub recommends waiting at least one minute
+	wait := time.Minute
+	if d, ok := parseRetryAfter(resp.Header); ok {
+		wait = d
+	}
+
+	return &ErrSecondaryRateLimit{ErrHTTPError: httpErr, RetryAfter: wait}
+}
+
+// parseRetryAfter parses the "Retry-After" header, which is either a number of seconds or an HTTP date.
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t), true
+	}
+	return 0, false
+}
+
+// waitForRate waits for resource's rate limit to reset if the last response for it reported no requests remaining, so
+// a request which would be rejected isn't sent. It doesn't wait when Retry is nil or the reset is further away than
+// Retry.MaxRateLimitWait; the request is sent and the rate limit error returned.
+func (apiInfo *APIInfo) waitForRate(ctx context.Context, resource string) error {
+	rate, ok := apiInfo.LastRate(resource)
+	if !ok || rate.Remaining > 0 || apiInfo.Retry == nil {
+		return nil
+	}
+
+	wait, ok := apiInfo.Retry.rateLimitWait(time.Until(rate.Reset))
+	if !ok || wait == 0 {
+		return nil
+	}
+
+	return sleepContext(ctx, wait)
+}
@@ -0,0 +1,153 @@
+package ghapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+const getRateLimitResponse = `{
+  "resources": {
+    "core": {
+      "limit": 5000,
+      "remaining": 4999,
+      "reset": 1372700873
+    },
+    "search": {
+      "limit": 30,
+      "remaining": 18,
+      "reset": 1372697452
+    },
+    "graphql": {
+      "limit": 5000,
+      "remaining": 4993,
+      "reset": 1372700389
+    }
+  },
+  "rate": {
+    "limit": 5000,
+    "remaining": 4999,
+    "reset": 1372700873
+  }
+}`
+
+func TestAPIInfo_GetRateLimit(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL != nil && r.URL.Path == "/rate_limit" {
+			expect(t, "GET", r.Method, "r.Method")
+			if _, err := w.Write([]byte(getRateLimitResponse)); err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	rateLimits, err := api.GetRateLimit()
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, 5000, rateLimits.Resources.Core.Limit, "Core.Limit")
+	expect(t, 4999, rateLimits.Resources.Core.Remaining, "Core.Remaining")
+	expect(t, time.Unix(1372700873, 0), rateLimits.Resources.Core.Reset, "Core.Reset")
+	expect(t, 30, rateLimits.Resources.Search.Limit, "Search.Limit")
+	expect(t, 18, rateLimits.Resources.Search.Remaining, "Search.Remaining")
+	expect(t, 4993, rateLimits.Resources.GraphQL.Remaining, "GraphQL.Remaining")
+
+	rate, ok := api.Issue.LastRate(SearchRateLimitResource)
+	expect(t, true, ok, "ok")
+	expect(t, 18, rate.Remaining, "rate.Remaining")
+}
+
+func TestAPIInfo_LastRate_RecordedFromHeaders(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4321")
+		w.Header().Set("X-RateLimit-Reset", "1372700873")
+		if _, err := w.Write([]byte(getIssue1347Response)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	defer ts.Close()
+
+	_, ok := api.LastRate(CoreRateLimitResource)
+	expect(t, false, ok, "ok")
+
+	_, err := api.Issue.GetIssue(1347)
+	waitSignal(t, signal)
+	expectNil(t, err, "err")
+
+	// the rate limit state is shared by all API structs created by NewGitHubAPI
+	rate, ok := api.PullRequest.LastRate(CoreRateLimitResource)
+	expect(t, true, ok, "ok")
+	expect(t, 5000, rate.Limit, "rate.Limit")
+	expect(t, 4321, rate.Remaining, "rate.Remaining")
+	expect(t, time.Unix(1372700873, 0), rate.Reset, "rate.Reset")
+}
+
+func TestAPIInfo_doHTTPRequest_ReturnsErrRateLimited(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "1372700873")
+		w.WriteHeader(403)
+		if _, err := w.Write([]byte(`{"message":"API rate limit exceeded"}`)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	defer ts.Close()
+
+	_, err := api.Organization.ListTeams()
+	waitSignal(t, signal)
+
+	e, ok := err.(*ErrRateLimited)
+	if !ok {
+		t.Fatalf("err is not of type *ErrRateLimited, is %T", err)
+	}
+	expect(t, 403, e.StatusCode, "e.StatusCode")
+	expect(t, "", e.Message, "e.Message")
+	expect(t, 0, e.Rate.Remaining, "e.Rate.Remaining")
+	expect(t, time.Unix(1372700873, 0), e.Rate.Reset, "e.Rate.Reset")
+	expect(t, true, IsHTTPError(err, 403), "IsHTTPError(err, 403)")
+}
+
+func TestAPIInfo_doHTTPRequest_ReturnsErrSecondaryRateLimit(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4000")
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(403)
+		if _, err := w.Write([]byte(`{"message":"You have exceeded a secondary rate limit."}`)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	defer ts.Close()
+
+	_, err := api.Repository.Get()
+	waitSignal(t, signal)
+
+	e, ok := err.(*ErrSecondaryRateLimit)
+	if !ok {
+		t.Fatalf("err is not of type *ErrSecondaryRateLimit, is %T", err)
+	}
+	expect(t, 30*time.Second, e.RetryAfter, "e.RetryAfter")
+}
+
+func TestOrganizationAPI_ListTeams_403PermissionMessage(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4000")
+		w.WriteHeader(403)
+	})
+	defer ts.Close()
+
+	_, err := api.Organization.ListTeams()
+	waitSignal(t, signal)
+
+	e, ok := err.(*ErrHTTPError)
+	if !ok {
+		t.Fatalf("err is not of type *ErrHTTPError, is %T", err)
+	}
+	expect(t, "does your authtoken have org:read permission?", e.Message, "e.Message")
+}
@@ -0,0 +1,176 @@
+package ghapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// squirrelGirlPreviewAcceptHeader is required by the Reactions API while it's in preview.
+const squirrelGirlPreviewAcceptHeader = "application/vnd.github.squirrel-girl-preview+json"
+
+// ReactionContent is the type of a reaction.
+type ReactionContent string
+
+const (
+	// ReactionPlusOne is a thumbs up; "+1".
+	ReactionPlusOne ReactionContent = "+1"
+	// ReactionMinusOne is a thumbs down; "-1".
+	ReactionMinusOne ReactionContent = "-1"
+	// ReactionLaugh is "laugh".
+	ReactionLaugh ReactionContent = "laugh"
+	// ReactionConfused is "confused".
+	ReactionConfused ReactionContent = "confused"
+	// ReactionHeart is "heart".
+	ReactionHeart ReactionContent = "heart"
+	// ReactionHooray is "hooray".
+	ReactionHooray ReactionContent = "hooray"
+	// ReactionRocket is "rocket".
+	ReactionRocket ReactionContent = "rocket"
+	// ReactionEyes is "eyes".
+	ReactionEyes ReactionContent = "eyes"
+)
+
+// Reaction is a user's reaction to an issue or comment.
+type Reaction struct {
+	ID        int             `json:"id"`
+	User      User            `json:"user"`
+	Content   ReactionContent `json:"content"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// ListIssueReactions lists the reactions to an issue or pull request.
+func (api *IssueAPI) ListIssueReactions(issueNumber int) ([]Reaction, error) {
+	return api.ListIssueReactionsContext(context.Background(), issueNumber)
+}
+
+// ListIssueReactionsContext is like ListIssueReactions but uses the provided context.
+func (api *IssueAPI) ListIssueReactionsContext(ctx context.Context, issueNumber int) ([]Reaction, error) {
+	var allReactions []Reaction
+	if err := api.ListIssueReactionsPagesContext(ctx, issueNumber, nil).All(&allReactions); err != nil {
+		return nil, err
+	}
+
+	return allReactions, nil
+}
+
+// ListIssueReactionsPages returns a PageIterator over the reactions to an issue or pull request. Each page decodes to
+// []Reaction.
+func (api *IssueAPI) ListIssueReactionsPages(issueNumber int, opts *ListOptions) *PageIterator {
+	return api.ListIssueReactionsPagesContext(context.Background(), issueNumber, opts)
+}
+
+// ListIssueReactionsPagesContext is like ListIssueReactionsPages but uses ctx for each page request.
+func (api *IssueAPI) ListIssueReactionsPagesContext(ctx context.Context, issueNumber int, opts *ListOptions) *PageIterator {
+	return api.reactionsPages(ctx, fmt.Sprintf("/repos/:owner/:repo/issues/%d/reactions", issueNumber), opts)
+}
+
+// AddIssueReaction adds a reaction to an issue or pull request. If the user has already reacted with content the
+// existing reaction is returned.
+func (api *IssueAPI) AddIssueReaction(issueNumber int, content ReactionContent) (*Reaction, error) {
+	return api.AddIssueReactionContext(context.Background(), issueNumber, content)
+}
+
+// AddIssueReactionContext is like AddIssueReaction but uses the provided context.
+func (api *IssueAPI) AddIssueReactionContext(ctx context.Context, issueNumber int, content ReactionContent) (*Reaction, error) {
+	return api.addReaction(ctx, fmt.Sprintf("/repos/:owner/:repo/issues/%d/reactions", issueNumber), content)
+}
+
+// DeleteIssueReaction deletes a reaction from an issue or pull request.
+func (api *IssueAPI) DeleteIssueReaction(issueNumber, reactionID int) error {
+	return api.DeleteIssueReactionContext(context.Background(), issueNumber, reactionID)
+}
+
+// DeleteIssueReactionContext is like DeleteIssueReaction but uses the provided context.
+func (api *IssueAPI) DeleteIssueReactionContext(ctx context.Context, issueNumber, reactionID int) error {
+	return api.deleteReaction(ctx, fmt.Sprintf("/repos/:owner/:repo/issues/%d/reactions/%d", issueNumber, reactionID))
+}
+
+// ListIssueCommentReactions lists the reactions to an issue comment.
+func (api *IssueAPI) ListIssueCommentReactions(commentID int) ([]Reaction, error) {
+	return api.ListIssueCommentReactionsContext(context.Background(), commentID)
+}
+
+// ListIssueCommentReactionsContext is like ListIssueCommentReactions but uses the provided context.
+func (api *IssueAPI) ListIssueCommentReactionsContext(ctx context.Context, commentID int) ([]Reaction, error) {
+	var allReactions []Reaction
+	if err := api.ListIssueCommentReactionsPagesContext(ctx, commentID, nil).All(&allReactions); err != nil {
+		return nil, err
+	}
+
+	return allReactions, nil
+}
+
+// ListIssueCommentReactionsPages returns a PageIterator over the reactions to an issue comment. Each page decodes to
+// []Reaction.
+func (api *IssueAPI) ListIssueCommentReactionsPages(commentID int, opts *ListOptions) *PageIterator {
+	return api.ListIssueCommentReactionsPagesContext(context.Background(), commentID, opts)
+}
+
+// ListIssueCommentReactionsPagesContext is like ListIssueCommentReactionsPages but uses ctx for each page request.
+func (api *IssueAPI) ListIssueCommentReactionsPagesContext(ctx context.Context, commentID int, opts *ListOptions) *PageIterator {
+	return api.reactionsPages(ctx, fmt.Sprintf("/repos/:owner/:repo/issues/comments/%d/reactions", commentID), opts)
+}
+
+// AddIssueCommentReaction adds a reaction to an issue comment. If the user has already reacted with content the
+// existing reaction is returned.
+func (api *IssueAPI) AddIssueCommentReaction(commentID int, content ReactionContent) (*Reaction, error) {
+	return api.AddIssueCommentReactionContext(context.Background(), commentID, content)
+}
+
+// AddIssueCommentReactionContext is like AddIssueCommentReaction but uses the provided context.
+func (api *IssueAPI) AddIssueCommentReactionContext(ctx context.Context, commentID int, content ReactionContent) (*Reaction, error) {
+	return api.addReaction(ctx, fmt.Sprintf("/repos/:owner/:repo/issues/comments/%d/reactions", commentID), content)
+}
+
+// DeleteIssueCommentReaction deletes a reaction from an issue comment.
+func (api *IssueAPI) DeleteIssueCommentReaction(commentID, reactionID int) error {
+	return api.DeleteIssueCommentReactionContext(context.Background(), commentID, reactionID)
+}
+
+// DeleteIssueCommentReactionContext is like DeleteIssueCommentReaction but uses the provided context.
+func (api *IssueAPI) DeleteIssueCommentReactionContext(ctx context.Context, commentID, reactionID int) error {
+	return api.deleteReaction(ctx, fmt.Sprintf("/repos/:owner/:repo/issues/comments/%d/reactions/%d", commentID, reactionID))
+}
+
+func (api *IssueAPI) reactionsPages(ctx context.Context, path string, opts *ListOptions) *PageIterator {
+	it := api.NewPageIteratorContext(ctx, api.getURL(path), opts)
+	it.accept = squirrelGirlPreviewAcceptHeader
+	return it
+}
+
+func (api *IssueAPI) addReaction(ctx context.Context, path string, content ReactionContent) (*Reaction, error) {
+	b, err := json.Marshal(struct {
+		Content ReactionContent `json:"content"`
+	}{content})
+	if err != nil {
+		return nil, err
+	}
+	body := string(b)
+
+	resp, err := api.doHTTPRequest(ctx, "POST", api.getURL(path), &body, squirrelGirlPreviewAcceptHeader)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var reaction Reaction
+
+	j := json.NewDecoder(resp.Body)
+	if err = j.Decode(&reaction); err != nil {
+		return nil, err
+	}
+
+	return &reaction, nil
+}
+
+func (api *IssueAPI) deleteReaction(ctx context.Context, path string) error {
+	resp, err := api.doHTTPRequest(ctx, "DELETE", api.getURL(path), nil, squirrelGirlPreviewAcceptHeader)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
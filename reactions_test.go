@@ -0,0 +1,69 @@
+package ghapi
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+const reaction1Response = `{"id":1,"user":{"login":"octocat","id":1},"content":"heart","created_at":"2016-05-20T20:09:31Z"}`
+
+func TestIssueApi_AddIssueReaction(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+
+		expectNil(t, err, "err")
+		expect(t, "POST", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/issues/1347/reactions", r.URL.Path, "r.URL.Path")
+		expect(t, squirrelGirlPreviewAcceptHeader, r.Header.Get("Accept"), "Accept")
+		expect(t, `{"content":"heart"}`, string(b), "r.Body")
+
+		w.WriteHeader(201)
+		_, err = w.Write([]byte(reaction1Response))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	reaction, err := api.Issue.AddIssueReaction(1347, ReactionHeart)
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, 1, reaction.ID, "reaction.ID")
+	expect(t, ReactionHeart, reaction.Content, "reaction.Content")
+	expect(t, "octocat", reaction.User.Login, "reaction.User.Login")
+}
+
+func TestIssueApi_ListIssueCommentReactions(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "GET", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/issues/comments/1/reactions", r.URL.Path, "r.URL.Path")
+		expect(t, squirrelGirlPreviewAcceptHeader, r.Header.Get("Accept"), "Accept")
+
+		_, err := w.Write([]byte("[" + reaction1Response + "]"))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	reactions, err := api.Issue.ListIssueCommentReactions(1)
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, 1, len(reactions), "len(reactions)")
+	expect(t, date("2016-05-20T20:09:31Z"), reactions[0].CreatedAt, "reactions[0].CreatedAt")
+}
+
+func TestIssueApi_DeleteIssueCommentReaction(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "DELETE", r.Method, "r.Method")
+		expect(t, "/repos/test_owner/test_repository/issues/comments/1/reactions/7", r.URL.Path, "r.URL.Path")
+		expect(t, squirrelGirlPreviewAcceptHeader, r.Header.Get("Accept"), "Accept")
+
+		w.WriteHeader(204)
+	})
+	defer ts.Close()
+
+	err := api.Issue.DeleteIssueCommentReaction(1, 7)
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+}
@@ -0,0 +1,183 @@
+package ghapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// RedactedValue replaces the values of redacted fields.
+const RedactedValue = "REDACTED"
+
+// DefaultRedactKeys are the key patterns used by DefaultRedactor.
+var DefaultRedactKeys = []string{"*secret*", "*token*", "*password*", "*private_key*", "authorization"}
+
+// DefaultMaxBodySize is the MaxBodySize used by DefaultRedactor.
+const DefaultMaxBodySize = 8192
+
+// Redactor masks secrets in the request and response bodies and URLs stored in ErrHTTPError, in the URL of a
+// *url.Error returned when a request can't be made, and in the URLs logged by LoggingMiddleware. Webhook configurations, for example, contain the hook's secret, and installation
+// access token responses contain the token.
+type Redactor struct {
+	// Keys are patterns matched against JSON object keys and URL query parameter names, case-insensitively, using
+	// path.Match syntax (for example "*secret*"). The values of matching keys are replaced with RedactedValue.
+	Keys []string
+	// MaxBodySize truncates bodies longer than this many bytes. Zero means no limit.
+	MaxBodySize int
+	// DropBodies removes bodies entirely, leaving only their size.
+	DropBodies bool
+}
+
+// DefaultRedactor returns the Redactor used when APIInfo.Redactor is nil. It masks DefaultRedactKeys and truncates
+// bodies to DefaultMaxBodySize.
+func DefaultRedactor() *Redactor {
+	return &Redactor{Keys: DefaultRedactKeys, MaxBodySize: DefaultMaxBodySize}
+}
+
+// WithRedactor sets the Redactor applied to errors. Use &Redactor{} to disable redaction.
+func WithRedactor(redactor *Redactor) Option {
+	return func(apiInfo *APIInfo) {
+		apiInfo.Redactor = redactor
+	}
+}
+
+// RedactBody masks the values of matching keys if body is JSON, then applies DropBodies and MaxBodySize. Bodies
+// which aren't JSON are only truncated.
+func (r *Redactor) RedactBody(body string) string {
+	if body == "" {
+		return body
+	}
+	if r.DropBodies {
+		return fmt.Sprintf("[%d bytes omitted]", len(body))
+	}
+
+	if len(r.Keys) != 0 {
+		body = r.redactJSON(body)
+	}
+
+	if r.MaxBodySize > 0 && len(body) > r.MaxBodySize {
+		return fmt.Sprintf("%s... [%d bytes truncated]", body[:r.MaxBodySize], len(body)-r.MaxBodySize)
+	}
+	return body
+}
+
+// RedactURL masks the values of matching query parameters and any password in rawurl.
+func (r *Redactor) RedactURL(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return rawurl
+	}
+
+	changed := false
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), RedactedValue)
+		changed = true
+	}
+
+	if u.RawQuery != "" && len(r.Keys) != 0 {
+		query := u.Query()
+		for key, values := range query {
+			if r.matchKey(key) {
+				for i := range values {
+					values[i] = RedactedValue
+				}
+				changed = true
+			}
+		}
+		if changed {
+			u.RawQuery = query.Encode()
+		}
+	}
+
+	if !changed {
+		return rawurl
+	}
+	return u.String()
+}
+
+// redactHTTPError redacts the request body, response body and URL of e in place.
+func (r *Redactor) redactHTTPError(e *ErrHTTPError) {
+	e.URL = r.RedactURL(e.URL)
+	e.RequestBody = r.RedactBody(e.RequestBody)
+	e.ResponseBody = r.RedactBody(e.ResponseBody)
+}
+
+// redactURLError redacts the URL of a *url.Error in err, returned by http.Client when a request can't be made, in
+// place. It returns err.
+func (r *Redactor) redactURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = r.RedactURL(urlErr.URL)
+	}
+	return err
+}
+
+func (r *Redactor) redactJSON(body string) string {
+	d := json.NewDecoder(strings.NewReader(body))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return body
+	}
+	if !r.redactValue(v) {
+		return body
+	}
+
+	var buf bytes.Buffer
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(v); err != nil {
+		return body
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// redactValue replaces the values of matching keys in v, recursively. It returns true if anything was replaced.
+func (r *Redactor) redactValue(v interface{}) bool {
+	changed := false
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for key, child := range val {
+			if r.matchKey(key) {
+				if _, isObject := child.(map[string]interface{}); !isObject {
+					val[key] = RedactedValue
+					changed = true
+					continue
+				}
+			}
+			if r.redactValue(child) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, child := range val {
+			if r.redactValue(child) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+func (r *Redactor) matchKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range r.Keys {
+		if ok, err := path.Match(strings.ToLower(pattern), key); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// redactor returns the Redactor for errors, DefaultRedactor if none is set.
+func (apiInfo *APIInfo) redactor() *Redactor {
+	if apiInfo.Redactor == nil {
+		return DefaultRedactor()
+	}
+	return apiInfo.Redactor
+}
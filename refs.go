@@ -1,6 +1,9 @@
 package ghapi
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+)
 
 // CreateRefResponse is returned by RefsAPI.Create.
 type CreateRefResponse struct {
@@ -16,6 +19,11 @@ type CreateRefResponse struct {
 // Create creates a reference in a repository from the specified SHA. 'ref' is the name of the fully qualified reference
 // (ie: refs/heads/master). If it doesn't start with 'refs' and have at least two slashes, it will be rejected.
 func (api *RefsAPI) Create(ref, sha string) (*CreateRefResponse, error) {
+	return api.CreateContext(context.Background(), ref, sha)
+}
+
+// CreateContext is like Create but uses the provided context.
+func (api *RefsAPI) CreateContext(ctx context.Context, ref, sha string) (*CreateRefResponse, error) {
 	url := api.getURL("/repos/:owner/:repo/git/refs")
 
 	body := struct {
@@ -31,7 +39,7 @@ func (api *RefsAPI) Create(ref, sha string) (*CreateRefResponse, error) {
 		return nil, err
 	}
 
-	resp, err := api.httpPost(url, string(b))
+	resp, err := api.httpPost(ctx, url, string(b))
 	if err != nil {
 		return nil, err
 	}
@@ -50,9 +58,14 @@ func (api *RefsAPI) Create(ref, sha string) (*CreateRefResponse, error) {
 // Get information about a ref. `ref` must be formatted as heads/master, not just master
 // https://developer.github.com/v3/git/refs/#get-a-reference
 func (api *RefsAPI) Get(ref string) (*CreateRefResponse, error) {
+	return api.GetContext(context.Background(), ref)
+}
+
+// GetContext is like Get but uses the provided context.
+func (api *RefsAPI) GetContext(ctx context.Context, ref string) (*CreateRefResponse, error) {
 	url := api.getURL("/repos/:owner/:repo/git/refs/")
 
-	resp, err := api.httpGet(url + ref)
+	resp, err := api.httpGet(ctx, url+ref)
 	if err != nil {
 		return nil, err
 	}
@@ -65,4 +78,4 @@ func (api *RefsAPI) Get(ref string) (*CreateRefResponse, error) {
 	}
 
 	return &refInfo, nil
-}
\ No newline at end of file
+}
@@ -1,6 +1,7 @@
 package ghapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -470,9 +471,14 @@ type Compare struct {
 
 // Get returns the repository information.
 func (api *RepositoryAPI) Get() (*RepositoryResponse, error) {
+	return api.GetContext(context.Background())
+}
+
+// GetContext is like Get but uses the provided context.
+func (api *RepositoryAPI) GetContext(ctx context.Context) (*RepositoryResponse, error) {
 	url := api.getURL("/repos/:owner/:repo")
 
-	resp, err := api.httpGet(url)
+	resp, err := api.httpGet(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -492,9 +498,14 @@ func (api *RepositoryAPI) Get() (*RepositoryResponse, error) {
 // Forking a Repository happens asynchronously.
 // Therefore, you may have to wait a short period before accessing the git objects.
 func (api *RepositoryAPI) ForkAsync() (*ForkResponse, error) {
+	return api.ForkAsyncContext(context.Background())
+}
+
+// ForkAsyncContext is like ForkAsync but uses the provided context.
+func (api *RepositoryAPI) ForkAsyncContext(ctx context.Context) (*ForkResponse, error) {
 	url := api.getURL("/repos/:owner/:repo/forks")
 
-	resp, err := api.httpPost(url, "")
+	resp, err := api.httpPost(ctx, url, "")
 	if err != nil {
 		return nil, err
 	}
@@ -513,9 +524,15 @@ func (api *RepositoryAPI) ForkAsync() (*ForkResponse, error) {
 // Fork performs a synchronous fork operation with a timeout period. See ForkAsync and IsReady methods for
 // more details.
 func (api *RepositoryAPI) Fork(timeout time.Duration) (*ForkResponse, error) {
+	return api.ForkContext(context.Background(), timeout)
+}
+
+// ForkContext is like Fork but uses the provided context. Waiting for the fork to complete stops when either the
+// timeout elapses or ctx is done.
+func (api *RepositoryAPI) ForkContext(ctx context.Context, timeout time.Duration) (*ForkResponse, error) {
 	timeoutChan := time.After(timeout)
 
-	forkResponse, err := api.ForkAsync()
+	forkResponse, err := api.ForkAsyncContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -525,7 +542,7 @@ func (api *RepositoryAPI) Fork(timeout time.Duration) (*ForkResponse, error) {
 	for {
 		select {
 		case <-ticker.C:
-			if ready, err := api.IsReady(); err != nil {
+			if ready, err := api.IsReadyContext(ctx); err != nil {
 				return forkResponse, err
 			} else if ready {
 				return forkResponse, nil
@@ -533,39 +550,66 @@ func (api *RepositoryAPI) Fork(timeout time.Duration) (*ForkResponse, error) {
 		case <-timeoutChan:
 			return forkResponse, fmt.Errorf("timeout (%v) waiting for fork of %s/%s to complete",
 				timeout, api.Owner, api.Repository)
+		case <-ctx.Done():
+			return forkResponse, ctx.Err()
 		}
 	}
 }
 
-// GetCommits returns commits for the repository.
+// GetCommits returns a single page of commits for the repository. Pages start at 1. See ListCommits to get all
+// commits.
 func (api *RepositoryAPI) GetCommits(page int) ([]RepositoryCommit, error) {
-	url := api.getURL("/repos/:owner/:repo/commits")
+	return api.GetCommitsContext(context.Background(), page)
+}
 
-	// TODO (judwhite)
-	// Link: <https://api.github.com/resource?page=2>; rel="next",
-	//       <https://api.github.com/resource?page=5>; rel="last"
+// GetCommitsContext is like GetCommits but uses the provided context.
+func (api *RepositoryAPI) GetCommitsContext(ctx context.Context, page int) ([]RepositoryCommit, error) {
+	url := api.getURL(fmt.Sprintf("/repos/:owner/:repo/commits?page=%d", page))
 
-	resp, err := api.httpGet(url)
-	if err != nil {
+	commits := []RepositoryCommit{}
+	if err := api.NewPageIteratorContext(ctx, url, &ListOptions{MaxPages: 1}).All(&commits); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	commits := []RepositoryCommit{}
+	return commits, nil
+}
 
-	j := json.NewDecoder(resp.Body)
-	if err = j.Decode(&commits); err != nil {
+// ListCommits returns all commits for the repository.
+func (api *RepositoryAPI) ListCommits() ([]RepositoryCommit, error) {
+	return api.ListCommitsContext(context.Background())
+}
+
+// ListCommitsContext is like ListCommits but uses the provided context.
+func (api *RepositoryAPI) ListCommitsContext(ctx context.Context) ([]RepositoryCommit, error) {
+	var allCommits []RepositoryCommit
+	if err := api.ListCommitsPagesContext(ctx, nil).All(&allCommits); err != nil {
 		return nil, err
 	}
 
-	return commits, nil
+	return allCommits, nil
+}
+
+// ListCommitsPages returns a PageIterator over the repository's commits. Each page decodes to []RepositoryCommit.
+func (api *RepositoryAPI) ListCommitsPages(opts *ListOptions) *PageIterator {
+	return api.ListCommitsPagesContext(context.Background(), opts)
+}
+
+// ListCommitsPagesContext is like ListCommitsPages but uses ctx for each page request.
+func (api *RepositoryAPI) ListCommitsPagesContext(ctx context.Context, opts *ListOptions) *PageIterator {
+	url := api.getURL("/repos/:owner/:repo/commits")
+	return api.NewPageIteratorContext(ctx, url, opts)
 }
 
 // GetCommit returns commit details for the specified SHA.
 func (api *RepositoryAPI) GetCommit(sha string) (*Commit, error) {
+	return api.GetCommitContext(context.Background(), sha)
+}
+
+// GetCommitContext is like GetCommit but uses the provided context.
+func (api *RepositoryAPI) GetCommitContext(ctx context.Context, sha string) (*Commit, error) {
 	url := api.getURL("/repos/:owner/:repo/commits/" + sha)
 
-	resp, err := api.httpGet(url)
+	resp, err := api.httpGet(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -583,7 +627,12 @@ func (api *RepositoryAPI) GetCommit(sha string) (*Commit, error) {
 
 // Exists returns true if the repository exists.
 func (api *RepositoryAPI) Exists() (bool, error) {
-	_, err := api.Get()
+	return api.ExistsContext(context.Background())
+}
+
+// ExistsContext is like Exists but uses the provided context.
+func (api *RepositoryAPI) ExistsContext(ctx context.Context) (bool, error) {
+	_, err := api.GetContext(ctx)
 	if err != nil {
 		if Is404(err) {
 			return false, nil
@@ -600,7 +649,12 @@ func (api *RepositoryAPI) Exists() (bool, error) {
 // This method is used internally by the Fork method. It can be used to check if a call to ForkAsync has completed
 // the fork operation.
 func (api *RepositoryAPI) IsReady() (bool, error) {
-	_, err := api.GetCommits(1)
+	return api.IsReadyContext(context.Background())
+}
+
+// IsReadyContext is like IsReady but uses the provided context.
+func (api *RepositoryAPI) IsReadyContext(ctx context.Context) (bool, error) {
+	_, err := api.GetCommitsContext(ctx, 1)
 	if err != nil {
 		if IsHTTPError(err, 409) {
 			return false, nil
@@ -610,12 +664,36 @@ func (api *RepositoryAPI) IsReady() (bool, error) {
 	return true, nil
 }
 
+// symmetraPreviewAcceptHeader is required to read and write label descriptions while they're in preview.
+const symmetraPreviewAcceptHeader = "application/vnd.github.symmetra-preview+json"
+
 // CreateLabel creates a label in the repository. color is a 6 character hex code without the leading #.
 func (api *RepositoryAPI) CreateLabel(name, color string) error {
+	return api.CreateLabelContext(context.Background(), name, color)
+}
+
+// CreateLabelContext is like CreateLabel but uses the provided context.
+func (api *RepositoryAPI) CreateLabelContext(ctx context.Context, name, color string) error {
+	return api.createLabel(ctx, name, color, nil)
+}
+
+// CreateLabelWithDescription creates a label in the repository with a description. color is a 6 character hex code
+// without the leading #.
+func (api *RepositoryAPI) CreateLabelWithDescription(name, color, description string) error {
+	return api.CreateLabelWithDescriptionContext(context.Background(), name, color, description)
+}
+
+// CreateLabelWithDescriptionContext is like CreateLabelWithDescription but uses the provided context.
+func (api *RepositoryAPI) CreateLabelWithDescriptionContext(ctx context.Context, name, color, description string) error {
+	return api.createLabel(ctx, name, color, &description)
+}
+
+func (api *RepositoryAPI) createLabel(ctx context.Context, name, color string, description *string) error {
 	body := struct {
-		Name  string `json:"name"`
-		Color string `json:"color"`
-	}{name, color}
+		Name        string  `json:"name"`
+		Color       string  `json:"color"`
+		Description *string `json:"description,omitempty"`
+	}{name, color, description}
 
 	b, err := json.Marshal(body)
 	if err != nil {
@@ -623,8 +701,9 @@ func (api *RepositoryAPI) CreateLabel(name, color string) error {
 	}
 
 	url := api.getURL("/repos/:owner/:repo/labels")
+	reqBody := string(b)
 
-	resp, err := api.httpPost(url, string(b))
+	resp, err := api.doHTTPRequest(ctx, "POST", url, &reqBody, symmetraPreviewAcceptHeader)
 	if err != nil {
 		return err
 	}
@@ -634,12 +713,34 @@ func (api *RepositoryAPI) CreateLabel(name, color string) error {
 	return err
 }
 
-// UpdateLabel updates a label in the repository. color is a 6 character hex code without the leading #.
+// UpdateLabel updates a label in the repository. color is a 6 character hex code without the leading #. The label's
+// description is unchanged.
 func (api *RepositoryAPI) UpdateLabel(origName, newName, color string) error {
+	return api.UpdateLabelContext(context.Background(), origName, newName, color)
+}
+
+// UpdateLabelContext is like UpdateLabel but uses the provided context.
+func (api *RepositoryAPI) UpdateLabelContext(ctx context.Context, origName, newName, color string) error {
+	return api.updateLabel(ctx, origName, newName, color, nil)
+}
+
+// UpdateLabelWithDescription updates a label in the repository, including its description. color is a 6 character
+// hex code without the leading #. An empty description removes it.
+func (api *RepositoryAPI) UpdateLabelWithDescription(origName, newName, color, description string) error {
+	return api.UpdateLabelWithDescriptionContext(context.Background(), origName, newName, color, description)
+}
+
+// UpdateLabelWithDescriptionContext is like UpdateLabelWithDescription but uses the provided context.
+func (api *RepositoryAPI) UpdateLabelWithDescriptionContext(ctx context.Context, origName, newName, color, description string) error {
+	return api.updateLabel(ctx, origName, newName, color, &description)
+}
+
+func (api *RepositoryAPI) updateLabel(ctx context.Context, origName, newName, color string, description *string) error {
 	body := struct {
-		Name  string `json:"name"`
-		Color string `json:"color"`
-	}{newName, color}
+		Name        string  `json:"name"`
+		Color       string  `json:"color"`
+		Description *string `json:"description,omitempty"`
+	}{newName, color, description}
 
 	b, err := json.Marshal(body)
 	if err != nil {
@@ -648,7 +749,9 @@ func (api *RepositoryAPI) UpdateLabel(origName, newName, color string) error {
 
 	apiURL := api.getURL("/repos/:owner/:repo/labels/" + url.PathEscape(origName))
 
-	resp, err := api.httpPatch(apiURL, string(b))
+	reqBody := string(b)
+
+	resp, err := api.doHTTPRequest(ctx, "PATCH", apiURL, &reqBody, symmetraPreviewAcceptHeader)
 	if err != nil {
 		return err
 	}
@@ -658,42 +761,61 @@ func (api *RepositoryAPI) UpdateLabel(origName, newName, color string) error {
 	return err
 }
 
-// GetLabels returns all labels for the repository.
-func (api *RepositoryAPI) GetLabels() ([]IssueLabel, error) {
-	var allLabels []IssueLabel
-
-	for page := 1; ; page++ {
-		url := api.getURL(fmt.Sprintf("/repos/:owner/:repo/labels?page=%d", page))
-
-		resp, err := api.httpGet(url)
-		if err != nil {
-			return nil, err
-		}
+// DeleteLabel deletes a label from the repository. The label is removed from every issue and pull request.
+func (api *RepositoryAPI) DeleteLabel(name string) error {
+	return api.DeleteLabelContext(context.Background(), name)
+}
 
-		var labels []IssueLabel
+// DeleteLabelContext is like DeleteLabel but uses the provided context.
+func (api *RepositoryAPI) DeleteLabelContext(ctx context.Context, name string) error {
+	apiURL := api.getURL("/repos/:owner/:repo/labels/" + url.PathEscape(name))
 
-		j := json.NewDecoder(resp.Body)
-		if err = j.Decode(&labels); err != nil {
-			resp.Body.Close()
-			return nil, err
-		}
+	resp, err := api.httpDelete(ctx, apiURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
 
-		resp.Body.Close()
+	return nil
+}
 
-		if len(labels) == 0 {
-			break
-		}
+// GetLabels returns all labels for the repository.
+func (api *RepositoryAPI) GetLabels() ([]IssueLabel, error) {
+	return api.GetLabelsContext(context.Background())
+}
 
-		allLabels = append(allLabels, labels...)
+// GetLabelsContext is like GetLabels but uses the provided context.
+func (api *RepositoryAPI) GetLabelsContext(ctx context.Context) ([]IssueLabel, error) {
+	var allLabels []IssueLabel
+	if err := api.GetLabelsPagesContext(ctx, nil).All(&allLabels); err != nil {
+		return nil, err
 	}
 
 	return allLabels, nil
 }
 
+// GetLabelsPages returns a PageIterator over the repository's labels. Each page decodes to []IssueLabel.
+func (api *RepositoryAPI) GetLabelsPages(opts *ListOptions) *PageIterator {
+	return api.GetLabelsPagesContext(context.Background(), opts)
+}
+
+// GetLabelsPagesContext is like GetLabelsPages but uses ctx for each page request.
+func (api *RepositoryAPI) GetLabelsPagesContext(ctx context.Context, opts *ListOptions) *PageIterator {
+	url := api.getURL("/repos/:owner/:repo/labels")
+	it := api.NewPageIteratorContext(ctx, url, opts)
+	it.accept = symmetraPreviewAcceptHeader
+	return it
+}
+
 // GetCompare returns the comparison between two refs
 func (api *RepositoryAPI) GetCompare(base string, head string) (*Compare, error) {
+	return api.GetCompareContext(context.Background(), base, head)
+}
+
+// GetCompareContext is like GetCompare but uses the provided context.
+func (api *RepositoryAPI) GetCompareContext(ctx context.Context, base string, head string) (*Compare, error) {
 	url := api.getURL(fmt.Sprintf("/repos/:owner/:repo/compare/%s...%s", base, head))
-	resp, err := api.httpGet(url)
+	resp, err := api.httpGet(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,157 @@
+package ghapi
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls automatic retries of failed requests. Retries are opt-in; set one with WithRetry.
+//
+// A request is retried when the connection times out, is refused or is reset, when GitHub returns 500, 502, 503 or
+// 504, or when a rate limit is exceeded and the wait until it resets is no longer than MaxRateLimitWait. Other
+// errors, such as TLS certificate errors or invalid URLs, aren't retried. A "Retry-After" header on a 5xx response is
+// honoured when it's no longer than MaxBackoff or MaxRateLimitWait, whichever is longer; otherwise the error is
+// returned. Only idempotent methods (GET, HEAD, PUT,
+// DELETE and OPTIONS) are retried unless RetryNonIdempotent is set.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first. Values less than 2 disable retries.
+	MaxAttempts int
+	// MinBackoff is the backoff before the first retry. It doubles on each subsequent retry.
+	MinBackoff time.Duration
+	// MaxBackoff caps the exponential backoff.
+	MaxBackoff time.Duration
+	// MaxRateLimitWait is the longest to wait for a rate limit to reset, or for a secondary rate limit's
+	// Retry-After to elapse. If the wait would be longer the error is returned. Zero disables retrying rate
+	// limited requests.
+	MaxRateLimitWait time.Duration
+	// RetryNonIdempotent allows POST and PATCH requests to be retried. Only enable this when repeating the
+	// request is safe, such as setting a commit status.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with 3 attempts, backoff between 500ms and 10s, and up to a minute of
+// waiting on rate limits.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:      3,
+		MinBackoff:       500 * time.Millisecond,
+		MaxBackoff:       10 * time.Second,
+		MaxRateLimitWait: time.Minute,
+	}
+}
+
+// WithRetry enables automatic retries using policy.
+func WithRetry(policy RetryPolicy) Option {
+	return func(apiInfo *APIInfo) {
+		apiInfo.Retry = &policy
+	}
+}
+
+// backoff returns how long to wait before retrying a request which failed with err on the specified attempt.
+// retry is false if the request should not be retried.
+func (p *RetryPolicy) backoff(method string, attempt int, err error) (wait time.Duration, retry bool) {
+	if p == nil || attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	if !p.RetryNonIdempotent && !isIdempotent(method) {
+		return 0, false
+	}
+	// a canceled or expired context is never worth retrying
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, false
+	}
+
+	switch e := err.(type) {
+	case *ErrRateLimited:
+		return p.rateLimitWait(time.Until(e.Rate.Reset))
+	case *ErrSecondaryRateLimit:
+		return p.rateLimitWait(e.RetryAfter)
+	case *ErrHTTPError:
+		switch e.StatusCode {
+		case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+		default:
+			return 0, false
+		}
+		if e.RetryAfter > 0 {
+			if e.RetryAfter > p.MaxBackoff && e.RetryAfter > p.MaxRateLimitWait {
+				return 0, false
+			}
+			return e.RetryAfter, true
+		}
+	default:
+		if !isTransientNetworkError(err) {
+			return 0, false
+		}
+	}
+
+	return p.exponential(attempt), true
+}
+
+// isTransientNetworkError returns true if err is a timeout, or the connection was refused or reset, which may succeed
+// if the request is repeated.
+func isTransientNetworkError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
+}
+
+func (p *RetryPolicy) rateLimitWait(wait time.Duration) (time.Duration, bool) {
+	if wait > p.MaxRateLimitWait || p.MaxRateLimitWait == 0 {
+		return 0, false
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
+
+// exponential returns MinBackoff * 2^(attempt-1), capped at MaxBackoff, with "equal jitter" applied so the wait is
+// between half and all of that value.
+func (p *RetryPolicy) exponential(attempt int) time.Duration {
+	wait := p.MinBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || wait < p.MaxBackoff); i++ {
+		wait *= 2
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(wait-half)+1))
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "PUT", "DELETE", "OPTIONS":
+		return true
+	}
+	return false
+}
+
+// sleepContext waits for d to elapse or ctx to be done, whichever happens first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
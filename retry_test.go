@@ -0,0 +1,240 @@
+package ghapi
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func makeRetryTestServer(handler func(attempt int32, w http.ResponseWriter, r *http.Request)) (*httptest.Server, *int32) {
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(atomic.AddInt32(&attempts, 1), w, r)
+	}))
+	return ts, &attempts
+}
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:      3,
+		MinBackoff:       time.Millisecond,
+		MaxBackoff:       5 * time.Millisecond,
+		MaxRateLimitWait: time.Second,
+	}
+}
+
+func TestRetry_RetriesTransientErrors(t *testing.T) {
+	ts, attempts := makeRetryTestServer(func(attempt int32, w http.ResponseWriter, r *http.Request) {
+		if attempt < 3 {
+			w.WriteHeader(502)
+			return
+		}
+		if _, err := w.Write([]byte(`{"name":"master"}`)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken, WithRetry(testRetryPolicy()))
+	branch, err := api.Branch.GetBranch("master")
+
+	expectNil(t, err, "err")
+	expect(t, "master", branch.Name, "branch.Name")
+	expect(t, int32(3), atomic.LoadInt32(attempts), "attempts")
+}
+
+func TestRetry_ReturnsLastErrorWhenAttemptsExhausted(t *testing.T) {
+	ts, attempts := makeRetryTestServer(func(attempt int32, w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(503)
+	})
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken, WithRetry(testRetryPolicy()))
+	_, err := api.Repository.Get()
+
+	expect(t, true, IsHTTPError(err, 503), "IsHTTPError(err, 503)")
+	expect(t, int32(3), atomic.LoadInt32(attempts), "attempts")
+}
+
+func TestRetry_DoesNotRetryClientErrors(t *testing.T) {
+	ts, attempts := makeRetryTestServer(func(attempt int32, w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+	})
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken, WithRetry(testRetryPolicy()))
+	_, err := api.Repository.Get()
+
+	expect(t, true, Is404(err), "Is404(err)")
+	expect(t, int32(1), atomic.LoadInt32(attempts), "attempts")
+}
+
+func TestRetry_DoesNotRetryPostByDefault(t *testing.T) {
+	ts, attempts := makeRetryTestServer(func(attempt int32, w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(502)
+	})
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken, WithRetry(testRetryPolicy()))
+	_, err := api.Status.SetStatus("abc", Success, "", "", "ci")
+
+	expect(t, true, IsHTTPError(err, 502), "IsHTTPError(err, 502)")
+	expect(t, int32(1), atomic.LoadInt32(attempts), "attempts")
+}
+
+func TestRetry_RetryNonIdempotentReplaysBody(t *testing.T) {
+	const expectedBody = `{"state":"success","target_url":"","description":"","context":"ci"}`
+
+	ts, attempts := makeRetryTestServer(func(attempt int32, w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect(t, expectedBody, string(b), "r.Body")
+		if attempt == 1 {
+			w.WriteHeader(503)
+			return
+		}
+		w.WriteHeader(201)
+		if _, err := w.Write([]byte(`{"state":"success"}`)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	defer ts.Close()
+
+	policy := testRetryPolicy()
+	policy.RetryNonIdempotent = true
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken, WithRetry(policy))
+	status, err := api.Status.SetStatus("abc", Success, "", "", "ci")
+
+	expectNil(t, err, "err")
+	expect(t, "success", status.State, "status.State")
+	expect(t, int32(2), atomic.LoadInt32(attempts), "attempts")
+}
+
+func TestRetry_HonoursRetryAfter(t *testing.T) {
+	ts, attempts := makeRetryTestServer(func(attempt int32, w http.ResponseWriter, r *http.Request) {
+		if attempt == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(403)
+			return
+		}
+		w.WriteHeader(200)
+	})
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken, WithRetry(testRetryPolicy()))
+	err := api.Branch.Protect("master", BranchProtection{})
+
+	expectNil(t, err, "err")
+	expect(t, int32(2), atomic.LoadInt32(attempts), "attempts")
+}
+
+func TestRetry_DoesNotWaitPastMaxRateLimitWait(t *testing.T) {
+	ts, attempts := makeRetryTestServer(func(attempt int32, w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "4102444800") // 2100-01-01
+		w.WriteHeader(403)
+	})
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken, WithRetry(testRetryPolicy()))
+	_, err := api.Repository.Get()
+
+	if _, ok := err.(*ErrRateLimited); !ok {
+		t.Fatalf("err is not of type *ErrRateLimited, is %T", err)
+	}
+	expect(t, int32(1), atomic.LoadInt32(attempts), "attempts")
+}
+
+func TestRetry_DoesNotWaitPastRetryAfter(t *testing.T) {
+	ts, attempts := makeRetryTestServer(func(attempt int32, w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(503)
+	})
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken, WithRetry(testRetryPolicy()))
+	_, err := api.Repository.Get()
+
+	var httpErr *ErrHTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("err is not an *ErrHTTPError, is %T", err)
+	}
+	expect(t, 503, httpErr.StatusCode, "httpErr.StatusCode")
+	expect(t, 2*time.Minute, httpErr.RetryAfter, "httpErr.RetryAfter")
+	expect(t, int32(1), atomic.LoadInt32(attempts), "attempts")
+}
+
+func TestRetry_RetriesRefusedConnections(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	ts.Close()
+
+	policy := testRetryPolicy()
+	var attempts int32
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken, WithRetry(policy),
+		WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				atomic.AddInt32(&attempts, 1)
+				return next.RoundTrip(req)
+			})
+		}))
+	_, err := api.Repository.Get()
+
+	expectNotNil(t, err, "err")
+	expect(t, int32(policy.MaxAttempts), atomic.LoadInt32(&attempts), "attempts")
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := testRetryPolicy()
+	urlErr := func(err error) error { return &url.Error{Op: "Get", URL: "https://api.github.com/repos", Err: err} }
+
+	cases := []struct {
+		name  string
+		err   error
+		retry bool
+		wait  time.Duration
+	}{
+		{name: "connection refused", err: urlErr(&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}), retry: true},
+		{name: "connection reset", err: urlErr(&net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}), retry: true},
+		{name: "timeout", err: urlErr(os.ErrDeadlineExceeded), retry: true},
+		{name: "x509", err: urlErr(x509.UnknownAuthorityError{})},
+		{name: "bad URL", err: urlErr(errors.New("unsupported protocol scheme \"ftp\""))},
+		{name: "context canceled", err: urlErr(context.Canceled)},
+		{name: "context deadline", err: urlErr(context.DeadlineExceeded)},
+		{name: "503 Retry-After", err: &ErrHTTPError{StatusCode: 503, RetryAfter: 500 * time.Millisecond}, retry: true, wait: 500 * time.Millisecond},
+		{name: "503 Retry-After too long", err: &ErrHTTPError{StatusCode: 503, RetryAfter: time.Hour}},
+		{name: "404", err: &ErrHTTPError{StatusCode: 404}},
+	}
+
+	for _, c := range cases {
+		wait, retry := policy.backoff("GET", 1, c.err)
+		expect(t, c.retry, retry, c.name+": retry")
+		if c.wait != 0 {
+			expect(t, c.wait, wait, c.name+": wait")
+		}
+	}
+}
+
+func TestRetryPolicy_exponential(t *testing.T) {
+	policy := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for attempt, max := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		max *= time.Millisecond
+		wait := policy.exponential(attempt + 1)
+		if wait < max/2 || wait > max {
+			t.Fatalf("attempt %d: wait %v not in [%v, %v]", attempt+1, wait, max/2, max)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+package ghapi
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// cloakPreviewAcceptHeader is required by the commit search API while it's in preview.
+const cloakPreviewAcceptHeader = "application/vnd.github.cloak-preview+json"
+
+// SearchQuery builds a search query from text and qualifiers such as "repo:", "is:" and "label:". Values containing
+// spaces are quoted.
+//
+//	q := ghapi.NewSearchQuery().Repo("octocat", "Hello-World").Is("pr").Is("open").Label("bug").
+//		Updated(">", time.Now().AddDate(0, 0, -7))
+//	result, err := api.Search.Issues(q.String(), nil)
+type SearchQuery struct {
+	terms []string
+}
+
+// NewSearchQuery returns a SearchQuery with optional search text.
+func NewSearchQuery(text ...string) *SearchQuery {
+	q := &SearchQuery{}
+	for _, t := range text {
+		q.Text(t)
+	}
+	return q
+}
+
+// Text adds search text. Text containing spaces is matched as a phrase.
+func (q *SearchQuery) Text(text string) *SearchQuery {
+	if text != "" {
+		q.terms = append(q.terms, quoteSearchValue(text))
+	}
+	return q
+}
+
+// Qualifier adds "key:value".
+func (q *SearchQuery) Qualifier(key, value string) *SearchQuery {
+	q.terms = append(q.terms, key+":"+quoteSearchValue(value))
+	return q
+}
+
+// Exclude adds "-key:value", excluding results which match the qualifier.
+func (q *SearchQuery) Exclude(key, value string) *SearchQuery {
+	q.terms = append(q.terms, "-"+key+":"+quoteSearchValue(value))
+	return q
+}
+
+// Repo adds "repo:owner/repo".
+func (q *SearchQuery) Repo(owner, repo string) *SearchQuery {
+	return q.Qualifier("repo", owner+"/"+repo)
+}
+
+// Org adds "org:org".
+func (q *SearchQuery) Org(org string) *SearchQuery {
+	return q.Qualifier("org", org)
+}
+
+// User adds "user:login", matching repositories owned by login.
+func (q *SearchQuery) User(login string) *SearchQuery {
+	return q.Qualifier("user", login)
+}
+
+// Is adds "is:value", for example "pr", "issue", "open", "closed" or "merged".
+func (q *SearchQuery) Is(value string) *SearchQuery {
+	return q.Qualifier("is", value)
+}
+
+// Label adds "label:name".
+func (q *SearchQuery) Label(name string) *SearchQuery {
+	return q.Qualifier("label", name)
+}
+
+// Author adds "author:login".
+func (q *SearchQuery) Author(login string) *SearchQuery {
+	return q.Qualifier("author", login)
+}
+
+// Assignee adds "assignee:login".
+func (q *SearchQuery) Assignee(login string) *SearchQuery {
+	return q.Qualifier("assignee", login)
+}
+
+// Mentions adds "mentions:login".
+func (q *SearchQuery) Mentions(login string) *SearchQuery {
+	return q.Qualifier("mentions", login)
+}
+
+// Involves adds "involves:login", matching issues login created, is assigned to, is mentioned in or commented on.
+func (q *SearchQuery) Involves(login string) *SearchQuery {
+	return q.Qualifier("involves", login)
+}
+
+// Language adds "language:language".
+func (q *SearchQuery) Language(language string) *SearchQuery {
+	return q.Qualifier("language", language)
+}
+
+// In adds "in:fields", restricting which fields the search text matches, for example "title", "body" or "comments".
+func (q *SearchQuery) In(fields ...string) *SearchQuery {
+	return q.Qualifier("in", strings.Join(fields, ","))
+}
+
+// Created adds "created:" with op and the date of t, for example Created(">=", t) adds "created:>=2017-07-14". op is
+// one of ">", ">=", "<", "<=", or "" for an exact date.
+func (q *SearchQuery) Created(op string, t time.Time) *SearchQuery {
+	return q.date("created", op, t)
+}
+
+// Updated adds "updated:" with op and the date of t. See Created.
+func (q *SearchQuery) Updated(op string, t time.Time) *SearchQuery {
+	return q.date("updated", op, t)
+}
+
+// Closed adds "closed:" with op and the date of t. See Created.
+func (q *SearchQuery) Closed(op string, t time.Time) *SearchQuery {
+	return q.date("closed", op, t)
+}
+
+// Merged adds "merged:" with op and the date of t. See Created.
+func (q *SearchQuery) Merged(op string, t time.Time) *SearchQuery {
+	return q.date("merged", op, t)
+}
+
+func (q *SearchQuery) date(key, op string, t time.Time) *SearchQuery {
+	q.terms = append(q.terms, key+":"+op+t.UTC().Format("2006-01-02"))
+	return q
+}
+
+// String returns the query.
+func (q *SearchQuery) String() string {
+	return strings.Join(q.terms, " ")
+}
+
+func quoteSearchValue(value string) string {
+	if strings.ContainsAny(value, " \t:") {
+		return `"` + strings.Replace(value, `"`, "", -1) + `"`
+	}
+	return value
+}
+
+// SearchOptions sorts search results. Zero values sort by best match.
+type SearchOptions struct {
+	// Sort depends on the search, for example "comments", "created" or "updated" for issues, and "stars" or "forks"
+	// for repositories.
+	Sort string
+	// Order is "asc" or "desc".
+	Order string
+}
+
+// SearchResult contains the fields common to every search response.
+type SearchResult struct {
+	// TotalCount is the number of matches. Only the first 1000 can be retrieved.
+	TotalCount int `json:"total_count"`
+	// IncompleteResults is true if the search timed out before finding every match. When collecting every page it's
+	// true if any page was incomplete.
+	IncompleteResults bool `json:"incomplete_results"`
+}
+
+func (r *SearchResult) searchResult() *SearchResult {
+	return r
+}
+
+// IssuesSearchResult is returned by SearchAPI.Issues. Pull requests are included; check IssueResponse.PullRequest.URL
+// to tell them apart.
+type IssuesSearchResult struct {
+	SearchResult
+	Items []IssueResponse `json:"items"`
+}
+
+// RepositoriesSearchResult is returned by SearchAPI.Repositories.
+type RepositoriesSearchResult struct {
+	SearchResult
+	Items []RepositoryResponse `json:"items"`
+}
+
+// UsersSearchResult is returned by SearchAPI.Users.
+type UsersSearchResult struct {
+	SearchResult
+	Items []User `json:"items"`
+}
+
+// CodeSearchItem is a file matching a code search.
+type CodeSearchItem struct {
+	Name       string             `json:"name"`
+	Path       string             `json:"path"`
+	SHA        string             `json:"sha"`
+	URL        string             `json:"url"`
+	GitURL     string             `json:"git_url"`
+	HTMLURL    string             `json:"html_url"`
+	Repository RepositoryResponse `json:"repository"`
+	Score      float64            `json:"score"`
+}
+
+// CodeSearchResult is returned by SearchAPI.Code.
+type CodeSearchResult struct {
+	SearchResult
+	Items []CodeSearchItem `json:"items"`
+}
+
+// CommitSearchItem is a commit matching a commit search.
+type CommitSearchItem struct {
+	RepositoryCommit
+	Repository RepositoryResponse `json:"repository"`
+	Score      float64            `json:"score"`
+}
+
+// CommitsSearchResult is returned by SearchAPI.Commits.
+type CommitsSearchResult struct {
+	SearchResult
+	Items []CommitSearchItem `json:"items"`
+}
+
+// searchPerPage is the page size used when collecting every result of a search; it's the most GitHub allows, so the
+// fewest requests are made against the search rate limit.
+const searchPerPage = 100
+
+// RateLimit returns the Search API rate limit seen on the most recent search response. Searches have a separate,
+// much lower limit than other calls; ok is false if no search has been made.
+//
+// Search iterators wait for the limit to reset before requesting a page when the previous response reported no
+// searches remaining, as long as APIInfo.Retry is set and the reset is within its MaxRateLimitWait. Otherwise the
+// request is made and *ErrRateLimited is returned; callers without a RetryPolicy should check RateLimit between
+// searches.
+func (api *SearchAPI) RateLimit() (rate Rate, ok bool) {
+	return api.LastRate(SearchRateLimitResource)
+}
+
+// Issues searches issues and pull requests. Every page of results is requested; GitHub returns at most 1000 results.
+// See https://help.github.com/articles/searching-issues-and-pull-requests/ for the query syntax, and SearchQuery.
+func (api *SearchAPI) Issues(query string, opts *SearchOptions) (*IssuesSearchResult, error) {
+	return api.IssuesContext(context.Background(), query, opts)
+}
+
+// IssuesContext is like Issues but uses the provided context.
+func (api *SearchAPI) IssuesContext(ctx context.Context, query string, opts *SearchOptions) (*IssuesSearchResult, error) {
+	var result IssuesSearchResult
+	it := api.IssuesPagesContext(ctx, query, opts, &ListOptions{PerPage: searchPerPage})
+	if err := collectSearchResults(it, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// IssuesPages returns a PageIterator over the results of an issue search. Each page decodes to *IssuesSearchResult.
+func (api *SearchAPI) IssuesPages(query string, opts *SearchOptions, listOpts *ListOptions) *PageIterator {
+	return api.IssuesPagesContext(context.Background(), query, opts, listOpts)
+}
+
+// IssuesPagesContext is like IssuesPages but uses ctx for each page request.
+func (api *SearchAPI) IssuesPagesContext(ctx context.Context, query string, opts *SearchOptions, listOpts *ListOptions) *PageIterator {
+	return api.searchPages(ctx, "issues", query, opts, listOpts, "")
+}
+
+// Repositories searches repositories. Every page of results is requested; GitHub returns at most 1000 results.
+func (api *SearchAPI) Repositories(query string, opts *SearchOptions) (*RepositoriesSearchResult, error) {
+	return api.RepositoriesContext(context.Background(), query, opts)
+}
+
+// RepositoriesContext is like Repositories but uses the provided context.
+func (api *SearchAPI) RepositoriesContext(ctx context.Context, query string, opts *SearchOptions) (*RepositoriesSearchResult, error) {
+	var result RepositoriesSearchResult
+	it := api.RepositoriesPagesContext(ctx, query, opts, &ListOptions{PerPage: searchPerPage})
+	if err := collectSearchResults(it, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// RepositoriesPages returns a PageIterator over the results of a repository search. Each page decodes to
+// *RepositoriesSearchResult.
+func (api *SearchAPI) RepositoriesPages(query string, opts *SearchOptions, listOpts *ListOptions) *PageIterator {
+	return api.RepositoriesPagesContext(context.Background(), query, opts, listOpts)
+}
+
+// RepositoriesPagesContext is like RepositoriesPages but uses ctx for each page request.
+func (api *SearchAPI) RepositoriesPagesContext(ctx context.Context, query string, opts *SearchOptions, listOpts *ListOptions) *PageIterator {
+	return api.searchPages(ctx, "repositories", query, opts, listOpts, "")
+}
+
+// Users searches users and organizations. Every page of results is requested; GitHub returns at most 1000 results.
+func (api *SearchAPI) Users(query string, opts *SearchOptions) (*UsersSearchResult, error) {
+	return api.UsersContext(context.Background(), query, opts)
+}
+
+// UsersContext is like Users but uses the provided context.
+func (api *SearchAPI) UsersContext(ctx context.Context, query string, opts *SearchOptions) (*UsersSearchResult, error) {
+	var result UsersSearchResult
+	it := api.UsersPagesContext(ctx, query, opts, &ListOptions{PerPage: searchPerPage})
+	if err := collectSearchResults(it, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// UsersPages returns a PageIterator over the results of a user search. Each page decodes to *UsersSearchResult.
+func (api *SearchAPI) UsersPages(query string, opts *SearchOptions, listOpts *ListOptions) *PageIterator {
+	return api.UsersPagesContext(context.Background(), query, opts, listOpts)
+}
+
+// UsersPagesContext is like UsersPages but uses ctx for each page request.
+func (api *SearchAPI) UsersPagesContext(ctx context.Context, query string, opts *SearchOptions, listOpts *ListOptions) *PageIterator {
+	return api.searchPages(ctx, "users", query, opts, listOpts, "")
+}
+
+// Code searches file contents. The query must include a "repo:", "org:" or "user:" qualifier. Every page of results
+// is requested; GitHub returns at most 1000 results.
+func (api *SearchAPI) Code(query string, opts *SearchOptions) (*CodeSearchResult, error) {
+	return api.CodeContext(context.Background(), query, opts)
+}
+
+// CodeContext is like Code but uses the provided context.
+func (api *SearchAPI) CodeContext(ctx context.Context, query string, opts *SearchOptions) (*CodeSearchResult, error) {
+	var result CodeSearchResult
+	it := api.CodePagesContext(ctx, query, opts, &ListOptions{PerPage: searchPerPage})
+	if err := collectSearchResults(it, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CodePages returns a PageIterator over the results of a code search. Each page decodes to *CodeSearchResult.
+func (api *SearchAPI) CodePages(query string, opts *SearchOptions, listOpts *ListOptions) *PageIterator {
+	return api.CodePagesContext(context.Background(), query, opts, listOpts)
+}
+
+// CodePagesContext is like CodePages but uses ctx for each page request.
+func (api *SearchAPI) CodePagesContext(ctx context.Context, query string, opts *SearchOptions, listOpts *ListOptions) *PageIterator {
+	return api.searchPages(ctx, "code", query, opts, listOpts, "")
+}
+
+// Commits searches commits on repositories' default branches. Every page of results is requested; GitHub returns at
+// most 1000 results.
+func (api *SearchAPI) Commits(query string, opts *SearchOptions) (*CommitsSearchResult, error) {
+	return api.CommitsContext(context.Background(), query, opts)
+}
+
+// CommitsContext is like Commits but uses the provided context.
+func (api *SearchAPI) CommitsContext(ctx context.Context, query string, opts *SearchOptions) (*CommitsSearchResult, error) {
+	var result CommitsSearchResult
+	it := api.CommitsPagesContext(ctx, query, opts, &ListOptions{PerPage: searchPerPage})
+	if err := collectSearchResults(it, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CommitsPages returns a PageIterator over the results of a commit search. Each page decodes to
+// *CommitsSearchResult.
+func (api *SearchAPI) CommitsPages(query string, opts *SearchOptions, listOpts *ListOptions) *PageIterator {
+	return api.CommitsPagesContext(context.Background(), query, opts, listOpts)
+}
+
+// CommitsPagesContext is like CommitsPages but uses ctx for each page request.
+func (api *SearchAPI) CommitsPagesContext(ctx context.Context, query string, opts *SearchOptions, listOpts *ListOptions) *PageIterator {
+	return api.searchPages(ctx, "commits", query, opts, listOpts, cloakPreviewAcceptHeader)
+}
+
+func (api *SearchAPI) searchPages(ctx context.Context, kind, query string, opts *SearchOptions, listOpts *ListOptions, accept string) *PageIterator {
+	q := url.Values{}
+	q.Set("q", query)
+	if opts != nil {
+		if opts.Sort != "" {
+			q.Set("sort", opts.Sort)
+		}
+		if opts.Order != "" {
+			q.Set("order", opts.Order)
+		}
+	}
+
+	it := api.NewPageIteratorContext(ctx, api.addBaseURL("/search/"+kind+"?"+q.Encode()), listOpts)
+	it.accept = accept
+	it.rateResource = SearchRateLimitResource
+	return it
+}
+
+// collectSearchResults decodes every page from it into result, which must be a pointer to one of the *SearchResult
+// types. TotalCount comes from the first page; IncompleteResults is true if any page was incomplete.
+func collectSearchResults(it *PageIterator, result interface{}) error {
+	rv := reflect.ValueOf(result).Elem()
+	items := rv.FieldByName("Items")
+	if !items.IsValid() || items.Kind() != reflect.Slice {
+		return errors.New("collectSearchResults: result must have an Items slice")
+	}
+
+	header := result.(interface{ searchResult() *SearchResult }).searchResult()
+	incomplete := false
+	for it.Next() {
+		page := reflect.New(rv.Type())
+		if err := it.Decode(page.Interface()); err != nil {
+			return err
+		}
+
+		pageHeader := page.Interface().(interface{ searchResult() *SearchResult }).searchResult()
+		if it.Pages() == 1 {
+			header.TotalCount = pageHeader.TotalCount
+		}
+		incomplete = incomplete || pageHeader.IncompleteResults
+
+		items.Set(reflect.AppendSlice(items, page.Elem().FieldByName("Items")))
+	}
+	header.IncompleteResults = incomplete
+
+	return it.Err()
+}
@@ -0,0 +1,180 @@
+package ghapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSearchQuery(t *testing.T) {
+	q := NewSearchQuery("crash on start").
+		Repo("octocat", "Hello-World").
+		Is("pr").
+		Is("open").
+		Label("good first issue").
+		Author("octocat").
+		Exclude("label", "wontfix").
+		In("title", "body").
+		Updated(">=", time.Date(2017, 7, 14, 23, 0, 0, 0, time.UTC))
+
+	expected := `"crash on start" repo:octocat/Hello-World is:pr is:open label:"good first issue" author:octocat ` +
+		`-label:wontfix in:title,body updated:>=2017-07-14`
+	expect(t, expected, q.String(), "q.String()")
+}
+
+// makeSearchTestServer returns a server which responds to issue searches with pages of one item, three pages in all.
+// The second page is incomplete.
+func makeSearchTestServer(t *testing.T) *httptest.Server {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "/search/issues", r.URL.Path, "r.URL.Path")
+		expect(t, "repo:octocat/Hello-World is:pr", r.URL.Query().Get("q"), "q")
+
+		page := 1
+		switch r.URL.Query().Get("page") {
+		case "2":
+			page = 2
+		case "3":
+			page = 3
+		}
+		if page < 3 {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/search/issues?q=repo%%3Aoctocat%%2FHello-World+is%%3Apr&page=%d>; rel="next"`, ts.URL, page+1))
+		}
+		w.Header().Set("X-RateLimit-Limit", "30")
+		w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(30-page))
+		w.Header().Set("X-RateLimit-Reset", "1500000000")
+		w.Header().Set("X-RateLimit-Resource", "search")
+
+		_, err := fmt.Fprintf(w, `{"total_count":%d,"incomplete_results":%t,"items":[{"number":%d}]}`, 4-page, page == 2, page)
+		expectNil(t, err, "err")
+	}))
+	return ts
+}
+
+func TestSearchAPI_Issues(t *testing.T) {
+	ts := makeSearchTestServer(t)
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken)
+
+	_, ok := api.Search.RateLimit()
+	expect(t, false, ok, "ok before searching")
+
+	result, err := api.Search.Issues(NewSearchQuery().Repo("octocat", "Hello-World").Is("pr").String(), nil)
+	expectNil(t, err, "err")
+	expect(t, 3, result.TotalCount, "result.TotalCount")
+	expect(t, true, result.IncompleteResults, "result.IncompleteResults")
+	expect(t, 3, len(result.Items), "len(result.Items)")
+	expect(t, 3, result.Items[2].Number, "result.Items[2].Number")
+
+	rate, ok := api.Search.RateLimit()
+	expect(t, true, ok, "ok after searching")
+	expect(t, 27, rate.Remaining, "rate.Remaining")
+	_, ok = api.LastRate(CoreRateLimitResource)
+	expect(t, false, ok, "core rate recorded")
+}
+
+func TestSearchAPI_IssuesPagesMaxItems(t *testing.T) {
+	ts := makeSearchTestServer(t)
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken)
+	it := api.Search.IssuesPages("repo:octocat/Hello-World is:pr", nil, &ListOptions{MaxItems: 2})
+
+	var numbers []int
+	for it.Next() {
+		var page IssuesSearchResult
+		expectNil(t, it.Decode(&page), "Decode")
+		for _, issue := range page.Items {
+			numbers = append(numbers, issue.Number)
+		}
+	}
+	expectNil(t, it.Err(), "it.Err()")
+	expect(t, 2, len(numbers), "len(numbers)")
+	expect(t, 2, it.Pages(), "it.Pages()")
+}
+
+func TestSearchAPI_IssuesWaitsForRateLimitReset(t *testing.T) {
+	var (
+		mtx   sync.Mutex
+		reset time.Time
+	)
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		if r.URL.Query().Get("page") == "2" {
+			if time.Now().Before(reset) {
+				t.Errorf("page 2 requested at %v, before the rate limit reset at %v", time.Now(), reset)
+			}
+			_, err := w.Write([]byte(`{"total_count":2,"incomplete_results":false,"items":[{"number":2}]}`))
+			expectNil(t, err, "err")
+			return
+		}
+
+		// the reset header has a resolution of one second
+		reset = time.Unix(time.Now().Add(time.Second).Unix(), 0)
+		w.Header().Set("Link", fmt.Sprintf(`<%s/search/issues?q=is%%3Apr&page=2>; rel="next"`, ts.URL))
+		w.Header().Set("X-RateLimit-Limit", "30")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset.Unix()))
+		w.Header().Set("X-RateLimit-Resource", "search")
+
+		_, err := w.Write([]byte(`{"total_count":2,"incomplete_results":false,"items":[{"number":1}]}`))
+		expectNil(t, err, "err")
+	}))
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken, WithRetry(testRetryPolicy()))
+
+	result, err := api.Search.Issues("is:pr", nil)
+	expectNil(t, err, "err")
+	expect(t, 2, len(result.Items), "len(result.Items)")
+}
+
+func TestSearchAPI_Commits(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "GET", r.Method, "r.Method")
+		expect(t, "/search/commits", r.URL.Path, "r.URL.Path")
+		expect(t, cloakPreviewAcceptHeader, r.Header.Get("Accept"), "Accept")
+		expect(t, "repo:octocat/Hello-World fix", r.URL.Query().Get("q"), "q")
+		expect(t, "author-date", r.URL.Query().Get("sort"), "sort")
+		expect(t, "desc", r.URL.Query().Get("order"), "order")
+
+		_, err := w.Write([]byte(`{"total_count":1,"incomplete_results":false,"items":[{"sha":"bb4cc8d3b2e14b3af5df699876dd4ff3acd00b7f",
+			"commit":{"message":"Fix the bug"},"repository":{"full_name":"octocat/Hello-World"},"score":4.9}]}`))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	result, err := api.Search.Commits("repo:octocat/Hello-World fix", &SearchOptions{Sort: "author-date", Order: "desc"})
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, 1, result.TotalCount, "result.TotalCount")
+	expect(t, "Fix the bug", result.Items[0].Commit.Message, "result.Items[0].Commit.Message")
+	expect(t, "octocat/Hello-World", result.Items[0].Repository.FullName, "result.Items[0].Repository.FullName")
+}
+
+func TestSearchAPI_Code(t *testing.T) {
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "/search/code", r.URL.Path, "r.URL.Path")
+		expect(t, "", r.URL.Query().Get("sort"), "sort")
+		expect(t, "100", r.URL.Query().Get("per_page"), "per_page")
+
+		_, err := w.Write([]byte(`{"total_count":1,"incomplete_results":false,"items":[{"name":"api.go","path":"api.go",
+			"repository":{"full_name":"judwhite/ghapi"}}]}`))
+		expectNil(t, err, "err")
+	})
+	defer ts.Close()
+
+	result, err := api.Search.Code("NewGitHubAPI repo:judwhite/ghapi", nil)
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	expect(t, "api.go", result.Items[0].Path, "result.Items[0].Path")
+}
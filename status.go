@@ -1,6 +1,7 @@
 package ghapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -68,8 +69,13 @@ type CommitCombinedStatus struct {
 // targetURL is the target URL to associate with this status. This URL will be linked from the GitHub UI to allow users
 // to easily see the 'source' of the Status.
 // description is a short description of the status (often a more detailed description of the state).
-// context is a string label to differentiate this status from the status of other systems/steps.
-func (api *StatusAPI) SetStatus(sha string, state StatusState, targetURL, description, context string) (*CommitStatus, error) {
+// statusContext is a string label to differentiate this status from the status of other systems/steps.
+func (api *StatusAPI) SetStatus(sha string, state StatusState, targetURL, description, statusContext string) (*CommitStatus, error) {
+	return api.SetStatusContext(context.Background(), sha, state, targetURL, description, statusContext)
+}
+
+// SetStatusContext is like SetStatus but uses the provided context.
+func (api *StatusAPI) SetStatusContext(ctx context.Context, sha string, state StatusState, targetURL, description, statusContext string) (*CommitStatus, error) {
 	url := api.getURL("/repos/:owner/:repo/statuses/") + sha
 
 	body := struct {
@@ -81,7 +87,7 @@ func (api *StatusAPI) SetStatus(sha string, state StatusState, targetURL, descri
 		State:       string(state),
 		TargetURL:   targetURL,
 		Description: description,
-		Context:     context,
+		Context:     statusContext,
 	}
 
 	b, err := json.Marshal(body)
@@ -89,7 +95,7 @@ func (api *StatusAPI) SetStatus(sha string, state StatusState, targetURL, descri
 		return nil, err
 	}
 
-	resp, err := api.httpPost(url, string(b))
+	resp, err := api.httpPost(ctx, url, string(b))
 	if err != nil {
 		return nil, err
 	}
@@ -107,9 +113,14 @@ func (api *StatusAPI) SetStatus(sha string, state StatusState, targetURL, descri
 
 // GetList lists statuses for a specific Ref. The Ref can be a SHA, a branch name, or a tag name.
 func (api *StatusAPI) GetList(ref string) ([]CommitStatus, error) {
+	return api.GetListContext(context.Background(), ref)
+}
+
+// GetListContext is like GetList but uses the provided context.
+func (api *StatusAPI) GetListContext(ctx context.Context, ref string) ([]CommitStatus, error) {
 	url := api.getURL(fmt.Sprintf("/repos/:owner/:repo/commits/%s/statuses", ref))
 
-	resp, err := api.httpGet(url)
+	resp, err := api.httpGet(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -128,9 +139,14 @@ func (api *StatusAPI) GetList(ref string) ([]CommitStatus, error) {
 // GetCombined returns a combined view of commit statuses for a given ref. The Ref can be a SHA, a branch name, or
 // a tag name. The returned state is either "success", "pending", or "failure" ("error" states become "failure").
 func (api *StatusAPI) GetCombined(ref string) (*CommitCombinedStatus, error) {
+	return api.GetCombinedContext(context.Background(), ref)
+}
+
+// GetCombinedContext is like GetCombined but uses the provided context.
+func (api *StatusAPI) GetCombinedContext(ctx context.Context, ref string) (*CommitCombinedStatus, error) {
 	url := api.getURL(fmt.Sprintf("/repos/:owner/:repo/commits/%s/status", ref))
 
-	resp, err := api.httpGet(url)
+	resp, err := api.httpGet(ctx, url)
 	if err != nil {
 		return nil, err
 	}
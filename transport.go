@@ -0,0 +1,72 @@
+package ghapi
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Middleware decorates an http.RoundTripper. Middleware is added to an APIInfo with WithMiddleware and every request
+// made by the API structs passes through it.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is an adapter to allow the use of ordinary functions as an http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Logger is the logging interface used by LoggingMiddleware. *log.Logger satisfies this interface.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// LoggingMiddleware logs the method, URL, status and duration of every request. The Authorization header is
+// never logged, and secrets in the URL are masked by DefaultRedactor.
+func LoggingMiddleware(logger Logger) Middleware {
+	return LoggingMiddlewareWithRedactor(logger, DefaultRedactor())
+}
+
+// LoggingMiddlewareWithRedactor is like LoggingMiddleware but masks secrets in the URL with redactor.
+func LoggingMiddlewareWithRedactor(logger Logger, redactor *Redactor) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			duration := time.Since(start)
+			reqURL := redactor.RedactURL(req.URL.String())
+			if err != nil {
+				logErr := err
+				if urlErr, ok := err.(*url.Error); ok {
+					logErr = &url.Error{Op: urlErr.Op, URL: redactor.RedactURL(urlErr.URL), Err: urlErr.Err}
+				}
+				logger.Printf("ghapi: %s %s error=%q (%v)", req.Method, reqURL, logErr, duration)
+				return nil, err
+			}
+			logger.Printf("ghapi: %s %s %d (%v)", req.Method, reqURL, resp.StatusCode, duration)
+			return resp, nil
+		})
+	}
+}
+
+// UserAgentMiddleware sets the "User-Agent" header on every request. GitHub requires a User-Agent and recommends
+// using your GitHub username or application name.
+func UserAgentMiddleware(userAgent string) Middleware {
+	return HeaderMiddleware(http.Header{"User-Agent": []string{userAgent}})
+}
+
+// HeaderMiddleware sets the specified static headers on every request, replacing any existing values.
+func HeaderMiddleware(header http.Header) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			// a RoundTripper must not modify the request it's given
+			req = req.Clone(req.Context())
+			for key, values := range header {
+				req.Header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
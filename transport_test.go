@@ -0,0 +1,111 @@
+package ghapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewGitHubAPI_WithHTTPClient(t *testing.T) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	api := NewGitHubAPI(expectedBaseURL, expectedOwner, expectedRepository, expectedAuthToken, WithHTTPClient(client))
+
+	expect(t, client, api.HTTPClient, "api.HTTPClient")
+	expect(t, client, api.Issue.HTTPClient, "api.Issue.HTTPClient")
+	expect(t, client, api.Organization.HTTPClient, "api.Organization.HTTPClient")
+	expect(t, client, api.httpClient(), "api.httpClient()")
+}
+
+func TestAPIInfo_httpClient_DefaultClient(t *testing.T) {
+	api := makeGitHubAPI()
+
+	expect(t, http.DefaultClient, api.httpClient(), "api.httpClient()")
+}
+
+func TestAPIInfo_doHTTPRequest_UsesHTTPClientTransport(t *testing.T) {
+	called := false
+	client := &http.Client{Transport: RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		expect(t, "token "+expectedAuthToken, req.Header.Get("Authorization"), "Authorization")
+		return &http.Response{StatusCode: 200, Status: "200 OK", Body: http.NoBody, Header: http.Header{}}, nil
+	})}
+	api := NewGitHubAPI(expectedBaseURL, expectedOwner, expectedRepository, expectedAuthToken, WithHTTPClient(client))
+
+	resp, err := api.Issue.httpGet(context.Background(), expectedBaseURL+"/test")
+
+	expectNil(t, err, "err")
+	expectNotNil(t, resp, "resp")
+	expect(t, true, called, "called")
+}
+
+func TestAPIInfo_doHTTPRequest_MiddlewareOrder(t *testing.T) {
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	ts, api, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {})
+	defer ts.Close()
+
+	api.Middleware = []Middleware{trace("first"), trace("second")}
+	resp, err := api.httpGet(context.Background(), ts.URL+"/test")
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	defer resp.Body.Close()
+	expect(t, "first,second", strings.Join(order, ","), "order")
+}
+
+func TestUserAgentMiddleware(t *testing.T) {
+	ts, _, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		expect(t, "test-agent/1.0", r.Header.Get("User-Agent"), "User-Agent")
+		expect(t, "custom", r.Header.Get("X-Custom"), "X-Custom")
+	})
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken, WithMiddleware(
+		UserAgentMiddleware("test-agent/1.0"),
+		HeaderMiddleware(http.Header{"X-Custom": []string{"custom"}}),
+	))
+	resp, err := api.Repository.httpGet(context.Background(), ts.URL+"/test")
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	defer resp.Body.Close()
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+
+	ts, _, signal := makeGitHubAPITestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(204)
+	})
+	defer ts.Close()
+
+	api := NewGitHubAPI(ts.URL, expectedOwner, expectedRepository, expectedAuthToken,
+		WithMiddleware(LoggingMiddleware(logger)))
+	resp, err := api.httpDelete(context.Background(), ts.URL+"/test")
+	waitSignal(t, signal)
+
+	expectNil(t, err, "err")
+	defer resp.Body.Close()
+
+	expected := fmt.Sprintf("ghapi: DELETE %s/test 204", ts.URL)
+	if !strings.HasPrefix(buf.String(), expected) {
+		t.Fatalf("log output %q doesn't start with %q", buf.String(), expected)
+	}
+	if strings.Contains(buf.String(), expectedAuthToken) {
+		t.Fatal("log output contains auth token")
+	}
+}
@@ -1,6 +1,7 @@
 package ghapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -67,13 +68,23 @@ type UserPublicOrganizationResponse struct {
 
 // GetUser returns user information by login name.
 func (api *UserAPI) GetUser(userName string) (*UserFull, error) {
+	return api.GetUserContext(context.Background(), userName)
+}
+
+// GetUserContext is like GetUser but uses the provided context.
+func (api *UserAPI) GetUserContext(ctx context.Context, userName string) (*UserFull, error) {
 	url := api.addBaseURL("/users/" + userName)
-	return api.GetUserByURL(url)
+	return api.GetUserByURLContext(ctx, url)
 }
 
 // GetUserByURL returns user information by URL.
 func (api *UserAPI) GetUserByURL(url string) (*UserFull, error) {
-	resp, err := api.httpGet(url)
+	return api.GetUserByURLContext(context.Background(), url)
+}
+
+// GetUserByURLContext is like GetUserByURL but uses the provided context.
+func (api *UserAPI) GetUserByURLContext(ctx context.Context, url string) (*UserFull, error) {
+	resp, err := api.httpGet(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -95,8 +106,13 @@ func (api *UserAPI) GetUserByURL(url string) (*UserFull, error) {
 // you need to fetch all of the organization memberships (public and private)
 // for the authenticated user, use the List your organizations API instead.
 func (api *UserAPI) GetPublicOrganizations(userName string) ([]UserPublicOrganizationResponse, error) {
+	return api.GetPublicOrganizationsContext(context.Background(), userName)
+}
+
+// GetPublicOrganizationsContext is like GetPublicOrganizations but uses the provided context.
+func (api *UserAPI) GetPublicOrganizationsContext(ctx context.Context, userName string) ([]UserPublicOrganizationResponse, error) {
 	url := api.addBaseURL(fmt.Sprintf("/users/%s/orgs", userName))
-	return api.GetPublicOrganizationsByURL(url)
+	return api.GetPublicOrganizationsByURLContext(ctx, url)
 }
 
 // GetPublicOrganizationsByURL gets public organization memberships for the specified user.
@@ -105,7 +121,12 @@ func (api *UserAPI) GetPublicOrganizations(userName string) ([]UserPublicOrganiz
 // you need to fetch all of the organization memberships (public and private)
 // for the authenticated user, use the List your organizations API instead.
 func (api *UserAPI) GetPublicOrganizationsByURL(url string) ([]UserPublicOrganizationResponse, error) {
-	resp, err := api.httpGet(url)
+	return api.GetPublicOrganizationsByURLContext(context.Background(), url)
+}
+
+// GetPublicOrganizationsByURLContext is like GetPublicOrganizationsByURL but uses the provided context.
+func (api *UserAPI) GetPublicOrganizationsByURLContext(ctx context.Context, url string) ([]UserPublicOrganizationResponse, error) {
+	resp, err := api.httpGet(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,393 @@
+package ghapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// DefaultMaxPayloadSize is the largest payload accepted by a WebhookServer by default. GitHub caps payloads at 25MB.
+const DefaultMaxPayloadSize = 25 << 20
+
+// WebhookServer is an http.Handler which receives GitHub webhook events. It validates each event's signature, decodes
+// the payload into its typed struct and calls the callbacks registered for the event type.
+//
+// OnPullRequest, OnPullRequestReview, OnPullRequestReviewComment, OnIssues, OnIssueComment, OnStatus, OnPush and
+// OnPing register callbacks which receive the typed payload. Other events, for example "release" or "create", are
+// registered with OnPayload, which passes the payload returned by ParsePayload, or On, which passes the raw body.
+//
+// Callbacks must be registered before the server starts handling requests. Responses are:
+//
+//   - 202 when Queue is set and the event was enqueued
+//   - 204 when the event was handled by every matching callback, or no callback matched
+//   - 400 when the body or "X-Github-Event" header is missing, or the payload isn't valid JSON
+//   - 401 when the signature is missing, malformed or doesn't match
+//   - 405 when the method isn't POST
+//   - 409 when Deliveries is set and the delivery GUID has already been seen
+//   - 413 when the body is larger than MaxPayloadSize
+//   - 500 when a callback returns an error, so GitHub records the delivery as failed
+//   - 503 when Queue is set and is full or shut down
+type WebhookServer struct {
+	// Policy validates event signatures.
+	Policy SignaturePolicy
+	// MaxPayloadSize is the largest body accepted. Defaults to DefaultMaxPayloadSize.
+	MaxPayloadSize int64
+	// Fallback is called for events with no registered callback. If nil, those events are acknowledged and ignored.
+	Fallback func(ctx context.Context, eventType GitHubEventType, body []byte) error
+	// Logger logs rejected events and callback errors. If nil, nothing is logged.
+	Logger Logger
+	// Deliveries, if set, rejects redelivered events. Events which aren't handled, because the payload can't be
+	// decoded or a callback fails, are forgotten so they can be redelivered; with Queue set, that's once the event
+	// has failed every attempt. A redelivered event is passed to every matching callback again, including those which
+	// succeeded the first time, so callbacks registered for the same event type should be idempotent.
+	Deliveries DeliveryStore
+	// Queue, if set, receives validated events, which are acknowledged without waiting for the callbacks. The queue's
+	// handler should be the server's HandleDelivery method.
+	Queue *EventQueue
+	// Journal, if set, records every validated delivery so it can be replayed with ReplayJournal. Events are still
+	// handled if they can't be recorded; the error is logged.
+	Journal *Journal
+
+	handlers map[GitHubEventType][]webhookHandler
+}
+
+type webhookHandler struct {
+	actions    []string
+	newPayload func() interface{}
+	handle     func(ctx context.Context, eventType GitHubEventType, body []byte, payload interface{}) error
+}
+
+// NewWebhookServer returns a WebhookServer which accepts events signed with any of secrets.
+func NewWebhookServer(secrets ...[]byte) *WebhookServer {
+	return &WebhookServer{
+		Policy:   SignaturePolicy{Secrets: secrets},
+		handlers: make(map[GitHubEventType][]webhookHandler),
+	}
+}
+
+// OnPullRequest registers a callback for "pull_request" events. If actions are specified the callback is only called
+// for those actions, for example Opened and Synchronize.
+func (s *WebhookServer) OnPullRequest(callback func(ctx context.Context, payload *PullRequestEventPayload) error, actions ...PullRequestAction) {
+	var filter []string
+	for _, action := range actions {
+		filter = append(filter, string(action))
+	}
+
+	s.register(PullRequestEventType, webhookHandler{
+		actions:    filter,
+		newPayload: func() interface{} { return &PullRequestEventPayload{} },
+		handle: func(ctx context.Context, eventType GitHubEventType, body []byte, payload interface{}) error {
+			return callback(ctx, payload.(*PullRequestEventPayload))
+		},
+	})
+}
+
+// OnPullRequestReview registers a callback for "pull_request_review" events. If actions are specified the callback is
+// only called for those actions ("submitted", "edited" or "dismissed").
+func (s *WebhookServer) OnPullRequestReview(callback func(ctx context.Context, payload *PullRequestReviewEventPayload) error, actions ...string) {
+	s.register(PullRequestReviewEventType, webhookHandler{
+		actions:    actions,
+		newPayload: func() interface{} { return &PullRequestReviewEventPayload{} },
+		handle: func(ctx context.Context, eventType GitHubEventType, body []byte, payload interface{}) error {
+			return callback(ctx, payload.(*PullRequestReviewEventPayload))
+		},
+	})
+}
+
+// OnPullRequestReviewComment registers a callback for "pull_request_review_comment" events. If actions are specified
+// the callback is only called for those actions ("created", "edited" or "deleted").
+func (s *WebhookServer) OnPullRequestReviewComment(callback func(ctx context.Context, payload *PullRequestReviewCommentEventPayload) error, actions ...string) {
+	s.register(PullRequestReviewCommentEventType, webhookHandler{
+		actions:    actions,
+		newPayload: func() interface{} { return &PullRequestReviewCommentEventPayload{} },
+		handle: func(ctx context.Context, eventType GitHubEventType, body []byte, payload interface{}) error {
+			return callback(ctx, payload.(*PullRequestReviewCommentEventPayload))
+		},
+	})
+}
+
+// OnIssues registers a callback for "issues" events. If actions are specified the callback is only called for those
+// actions, for example "opened" and "labeled".
+func (s *WebhookServer) OnIssues(callback func(ctx context.Context, payload *IssuesEventPayload) error, actions ...string) {
+	s.register(IssuesEventType, webhookHandler{
+		actions:    actions,
+		newPayload: func() interface{} { return &IssuesEventPayload{} },
+		handle: func(ctx context.Context, eventType GitHubEventType, body []byte, payload interface{}) error {
+			return callback(ctx, payload.(*IssuesEventPayload))
+		},
+	})
+}
+
+// OnIssueComment registers a callback for "issue_comment" events. If actions are specified the callback is only
+// called for those actions ("created", "edited" or "deleted").
+func (s *WebhookServer) OnIssueComment(callback func(ctx context.Context, payload *IssueCommentEventPayload) error, actions ...string) {
+	s.register(IssueCommentEventType, webhookHandler{
+		actions:    actions,
+		newPayload: func() interface{} { return &IssueCommentEventPayload{} },
+		handle: func(ctx context.Context, eventType GitHubEventType, body []byte, payload interface{}) error {
+			return callback(ctx, payload.(*IssueCommentEventPayload))
+		},
+	})
+}
+
+// OnPush registers a callback for "push" events.
+func (s *WebhookServer) OnPush(callback func(ctx context.Context, payload *PushEventPayload) error) {
+	s.register(PushEventType, webhookHandler{
+		newPayload: func() interface{} { return &PushEventPayload{} },
+		handle: func(ctx context.Context, eventType GitHubEventType, body []byte, payload interface{}) error {
+			return callback(ctx, payload.(*PushEventPayload))
+		},
+	})
+}
+
+// OnStatus registers a callback for "status" events, sent when a commit status changes.
+func (s *WebhookServer) OnStatus(callback func(ctx context.Context, payload *StatusEventPayload) error) {
+	s.register(StatusEventType, webhookHandler{
+		newPayload: func() interface{} { return &StatusEventPayload{} },
+		handle: func(ctx context.Context, eventType GitHubEventType, body []byte, payload interface{}) error {
+			return callback(ctx, payload.(*StatusEventPayload))
+		},
+	})
+}
+
+// OnPing registers a callback for "ping" events, sent when a hook is created or pinged.
+func (s *WebhookServer) OnPing(callback func(ctx context.Context, payload *PingEventPayload) error) {
+	s.register(PingEventType, webhookHandler{
+		newPayload: func() interface{} { return &PingEventPayload{} },
+		handle: func(ctx context.Context, eventType GitHubEventType, body []byte, payload interface{}) error {
+			return callback(ctx, payload.(*PingEventPayload))
+		},
+	})
+}
+
+// OnPayload registers a callback for events of the specified type which receives the payload struct ParsePayload
+// returns for it, for example *ReleaseEventPayload for ReleaseEventType. If actions are specified the callback is only
+// called for those actions. payload is nil for event types without a payload struct; use On for those.
+func (s *WebhookServer) OnPayload(eventType GitHubEventType, callback func(ctx context.Context, eventType GitHubEventType, payload interface{}) error, actions ...string) {
+	s.register(eventType, webhookHandler{
+		actions:    actions,
+		newPayload: eventPayloads[eventType],
+		handle: func(ctx context.Context, eventType GitHubEventType, body []byte, payload interface{}) error {
+			return callback(ctx, eventType, payload)
+		},
+	})
+}
+
+// On registers a callback which receives the raw body of events of the specified type. If actions are specified the
+// callback is only called for those actions.
+func (s *WebhookServer) On(eventType GitHubEventType, callback func(ctx context.Context, eventType GitHubEventType, body []byte) error, actions ...string) {
+	s.register(eventType, webhookHandler{
+		actions: actions,
+		handle: func(ctx context.Context, eventType GitHubEventType, body []byte, payload interface{}) error {
+			return callback(ctx, eventType, body)
+		},
+	})
+}
+
+func (s *WebhookServer) register(eventType GitHubEventType, handler webhookHandler) {
+	if s.handlers == nil {
+		s.handlers = make(map[GitHubEventType][]webhookHandler)
+	}
+	s.handlers[eventType] = append(s.handlers[eventType], handler)
+}
+
+// ServeHTTP handles a webhook event.
+func (s *WebhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		w.Header().Set("Allow", "POST")
+		s.reject(w, r, http.StatusMethodNotAllowed, nil)
+		return
+	}
+
+	if r.Body == nil {
+		s.reject(w, r, http.StatusBadRequest, ErrHTTPRequestBodyNil)
+		return
+	}
+
+	maxPayloadSize := s.MaxPayloadSize
+	if maxPayloadSize <= 0 {
+		maxPayloadSize = DefaultMaxPayloadSize
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxPayloadSize+1))
+	if err != nil {
+		s.reject(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if int64(len(body)) > maxPayloadSize {
+		s.reject(w, r, http.StatusRequestEntityTooLarge, nil)
+		return
+	}
+
+	if err = s.Policy.ValidateEvent(body, r.Header); err != nil {
+		s.reject(w, r, http.StatusUnauthorized, err)
+		return
+	}
+
+	eventType, err := GetEventType(r.Header)
+	if err != nil {
+		s.reject(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	deliveryID := r.Header.Get("X-GitHub-Delivery")
+	if s.Deliveries != nil {
+		if deliveryID, err = GetDeliveryID(r.Header); err != nil {
+			s.reject(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if err = checkDelivery(s.Deliveries, deliveryID); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, ErrDuplicateDelivery) {
+				status = http.StatusConflict
+			}
+			s.reject(w, r, status, err)
+			return
+		}
+	}
+
+	if s.Journal != nil {
+		s.record(r, eventType, body, deliveryID)
+	}
+
+	if s.Queue != nil {
+		s.enqueue(w, r, eventType, body, deliveryID)
+		return
+	}
+
+	status, err := s.dispatch(r.Context(), eventType, body)
+	if err != nil {
+		s.forget(deliveryID)
+		s.reject(w, r, status, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleDelivery calls the callbacks registered for the delivery's event type. It's the handler for an EventQueue
+// set as the server's Queue:
+//
+//	s := ghapi.NewWebhookServer(secret)
+//	s.Queue = ghapi.NewEventQueue(s.HandleDelivery)
+//	s.Queue.Start()
+func (s *WebhookServer) HandleDelivery(ctx context.Context, delivery *Delivery) error {
+	_, err := s.dispatch(ctx, delivery.EventType, delivery.Body)
+	return err
+}
+
+// enqueue adds a validated event to s.Queue and acknowledges it.
+func (s *WebhookServer) enqueue(w http.ResponseWriter, r *http.Request, eventType GitHubEventType, body []byte, deliveryID string) {
+	// callbacks run after the response is sent, so catch bad JSON while GitHub can still see the error
+	if !json.Valid(body) {
+		s.forget(deliveryID)
+		s.reject(w, r, http.StatusBadRequest, errors.New("payload is not valid JSON"))
+		return
+	}
+
+	err := s.Queue.Enqueue(&Delivery{
+		ID:        deliveryID,
+		HookID:    GetHookID(r.Header),
+		EventType: eventType,
+		Header:    r.Header,
+		Body:      body,
+		failed:    func(error) { s.forget(deliveryID) },
+	})
+	if err != nil {
+		s.forget(deliveryID)
+		s.reject(w, r, http.StatusServiceUnavailable, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// record adds a validated event to s.Journal.
+func (s *WebhookServer) record(r *http.Request, eventType GitHubEventType, body []byte, deliveryID string) {
+	err := s.Journal.Record(&Delivery{
+		ID:        deliveryID,
+		HookID:    GetHookID(r.Header),
+		EventType: eventType,
+		Header:    r.Header,
+		Body:      body,
+	})
+	if err != nil && s.Logger != nil {
+		s.Logger.Printf("ghapi: webhook delivery %s could not be journaled: %v", deliveryID, err)
+	}
+}
+
+// forget removes a delivery which wasn't processed from s.Deliveries so GitHub can redeliver it.
+func (s *WebhookServer) forget(deliveryID string) {
+	if s.Deliveries == nil || deliveryID == "" {
+		return
+	}
+	if err := s.Deliveries.Forget(deliveryID); err != nil && s.Logger != nil {
+		s.Logger.Printf("ghapi: webhook delivery %s could not be forgotten: %v", deliveryID, err)
+	}
+}
+
+// dispatch calls the callbacks registered for eventType. It returns the status code to respond with if there's an
+// error.
+func (s *WebhookServer) dispatch(ctx context.Context, eventType GitHubEventType, body []byte) (int, error) {
+	handlers := s.handlers[eventType]
+	if len(handlers) == 0 {
+		if s.Fallback == nil {
+			return 0, nil
+		}
+		if err := s.Fallback(ctx, eventType, body); err != nil {
+			return http.StatusInternalServerError, err
+		}
+		return 0, nil
+	}
+
+	var action struct {
+		Action string `json:"action"`
+	}
+	if err := json.Unmarshal(body, &action); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	for _, handler := range handlers {
+		if !handler.matchesAction(action.Action) {
+			continue
+		}
+
+		var payload interface{}
+		if handler.newPayload != nil {
+			payload = handler.newPayload()
+			if err := json.Unmarshal(body, payload); err != nil {
+				return http.StatusBadRequest, err
+			}
+		}
+
+		if err := handler.handle(ctx, eventType, body, payload); err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	return 0, nil
+}
+
+func (h webhookHandler) matchesAction(action string) bool {
+	if len(h.actions) == 0 {
+		return true
+	}
+	for _, a := range h.actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *WebhookServer) reject(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if s.Logger != nil {
+		if err == nil {
+			err = errors.New(http.StatusText(status))
+		}
+		s.Logger.Printf("ghapi: webhook %s %s rejected with %d: %v", r.Header.Get("X-Github-Event"), r.URL.Path, status, err)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
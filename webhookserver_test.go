@@ -0,0 +1,253 @@
+package ghapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var webhookTestSecret = []byte("webhook_secret")
+
+func makeWebhookRequest(t *testing.T, eventType GitHubEventType, body string) *http.Request {
+	req, err := http.NewRequest("POST", "/webhook", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, sha256Sig := signTestBody(t, webhookTestSecret, []byte(body))
+	req.Header.Set("X-Hub-Signature-256", sha256Sig)
+	req.Header.Set("X-Github-Event", string(eventType))
+	return req
+}
+
+func serveWebhook(s *WebhookServer, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	return w
+}
+
+func TestWebhookServer_OnPullRequest(t *testing.T) {
+	s := NewWebhookServer(webhookTestSecret)
+
+	var got *PullRequestEventPayload
+	s.OnPullRequest(func(ctx context.Context, payload *PullRequestEventPayload) error {
+		got = payload
+		return nil
+	}, Opened, Synchronize)
+
+	w := serveWebhook(s, makeWebhookRequest(t, PullRequestEventType,
+		`{"action":"opened","number":5,"pull_request":{"title":"Add feature"}}`))
+
+	expect(t, http.StatusNoContent, w.Code, "w.Code")
+	expectNotNil(t, got, "got")
+	expect(t, Opened, got.Action, "got.Action")
+	expect(t, 5, got.Number, "got.Number")
+	expect(t, "Add feature", got.PullRequest.Title, "got.PullRequest.Title")
+}
+
+func TestWebhookServer_ActionFilter(t *testing.T) {
+	s := NewWebhookServer(webhookTestSecret)
+
+	var calls int
+	s.OnPullRequest(func(ctx context.Context, payload *PullRequestEventPayload) error {
+		calls++
+		return nil
+	}, Opened, Synchronize)
+
+	w := serveWebhook(s, makeWebhookRequest(t, PullRequestEventType, `{"action":"labeled","number":5}`))
+	expect(t, http.StatusNoContent, w.Code, "w.Code")
+	expect(t, 0, calls, "calls")
+
+	w = serveWebhook(s, makeWebhookRequest(t, PullRequestEventType, `{"action":"synchronize","number":5}`))
+	expect(t, http.StatusNoContent, w.Code, "w.Code")
+	expect(t, 1, calls, "calls")
+}
+
+func TestWebhookServer_TypedCallbacks(t *testing.T) {
+	s := NewWebhookServer(webhookTestSecret)
+
+	var calls []string
+	s.OnIssueComment(func(ctx context.Context, payload *IssueCommentEventPayload) error {
+		calls = append(calls, "issue_comment:"+payload.Comment.Body)
+		return nil
+	}, "created")
+	s.OnPush(func(ctx context.Context, payload *PushEventPayload) error {
+		calls = append(calls, "push:"+payload.Ref)
+		return nil
+	})
+	s.OnPing(func(ctx context.Context, payload *PingEventPayload) error {
+		calls = append(calls, "ping:"+payload.Zen)
+		return nil
+	})
+	s.On(StatusEventType, func(ctx context.Context, eventType GitHubEventType, body []byte) error {
+		calls = append(calls, "status:"+string(body))
+		return nil
+	})
+
+	serveWebhook(s, makeWebhookRequest(t, IssueCommentEventType, `{"action":"created","comment":{"body":"LGTM"}}`))
+	serveWebhook(s, makeWebhookRequest(t, IssueCommentEventType, `{"action":"deleted","comment":{"body":"LGTM"}}`))
+	serveWebhook(s, makeWebhookRequest(t, PushEventType, `{"ref":"refs/heads/master"}`))
+	serveWebhook(s, makeWebhookRequest(t, PingEventType, `{"zen":"Keep it logically awesome."}`))
+	serveWebhook(s, makeWebhookRequest(t, StatusEventType, `{"state":"success"}`))
+
+	expected := []string{
+		"issue_comment:LGTM",
+		"push:refs/heads/master",
+		"ping:Keep it logically awesome.",
+		`status:{"state":"success"}`,
+	}
+	expect(t, len(expected), len(calls), "len(calls)")
+	for i := range expected {
+		expect(t, expected[i], calls[i], "calls[i]")
+	}
+}
+
+func TestWebhookServer_MoreTypedCallbacks(t *testing.T) {
+	s := NewWebhookServer(webhookTestSecret)
+
+	var calls []string
+	s.OnIssues(func(ctx context.Context, payload *IssuesEventPayload) error {
+		calls = append(calls, "issues:"+payload.Issue.Title)
+		return nil
+	}, "opened")
+	s.OnPullRequestReview(func(ctx context.Context, payload *PullRequestReviewEventPayload) error {
+		calls = append(calls, "pull_request_review:"+payload.Review.State)
+		return nil
+	})
+	s.OnPullRequestReviewComment(func(ctx context.Context, payload *PullRequestReviewCommentEventPayload) error {
+		calls = append(calls, "pull_request_review_comment:"+payload.Comment.Path)
+		return nil
+	})
+	s.OnStatus(func(ctx context.Context, payload *StatusEventPayload) error {
+		calls = append(calls, "status:"+string(payload.State))
+		return nil
+	})
+	s.OnPayload(ReleaseEventType, func(ctx context.Context, eventType GitHubEventType, payload interface{}) error {
+		calls = append(calls, "release:"+payload.(*ReleaseEventPayload).Release.TagName)
+		return nil
+	}, "published")
+
+	serveWebhook(s, makeWebhookRequest(t, IssuesEventType, `{"action":"opened","issue":{"title":"Crash on start"}}`))
+	serveWebhook(s, makeWebhookRequest(t, IssuesEventType, `{"action":"closed","issue":{"title":"Crash on start"}}`))
+	serveWebhook(s, makeWebhookRequest(t, PullRequestReviewEventType, `{"action":"submitted","review":{"state":"approved"}}`))
+	serveWebhook(s, makeWebhookRequest(t, PullRequestReviewCommentEventType, `{"action":"created","comment":{"path":"api.go"}}`))
+	serveWebhook(s, makeWebhookRequest(t, StatusEventType, `{"state":"success"}`))
+	serveWebhook(s, makeWebhookRequest(t, ReleaseEventType, `{"action":"published","release":{"tag_name":"v1.0.0"}}`))
+
+	expected := []string{
+		"issues:Crash on start",
+		"pull_request_review:approved",
+		"pull_request_review_comment:api.go",
+		"status:success",
+		"release:v1.0.0",
+	}
+	expect(t, len(expected), len(calls), "len(calls)")
+	for i := range expected {
+		expect(t, expected[i], calls[i], "calls[i]")
+	}
+}
+
+func TestWebhookServer_Fallback(t *testing.T) {
+	s := NewWebhookServer(webhookTestSecret)
+
+	// unknown events are acknowledged when there's no fallback
+	w := serveWebhook(s, makeWebhookRequest(t, WatchEventType, `{"action":"started"}`))
+	expect(t, http.StatusNoContent, w.Code, "w.Code")
+
+	var fallbackType GitHubEventType
+	s.Fallback = func(ctx context.Context, eventType GitHubEventType, body []byte) error {
+		fallbackType = eventType
+		return nil
+	}
+	w = serveWebhook(s, makeWebhookRequest(t, WatchEventType, `{"action":"started"}`))
+	expect(t, http.StatusNoContent, w.Code, "w.Code")
+	expect(t, WatchEventType, fallbackType, "fallbackType")
+}
+
+func TestWebhookServer_CallbackErrorReturns500(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewWebhookServer(webhookTestSecret)
+	s.Logger = log.New(&buf, "", 0)
+	s.OnPush(func(ctx context.Context, payload *PushEventPayload) error {
+		return errors.New("database unavailable")
+	})
+
+	w := serveWebhook(s, makeWebhookRequest(t, PushEventType, `{"ref":"refs/heads/master"}`))
+
+	expect(t, http.StatusInternalServerError, w.Code, "w.Code")
+	if !strings.Contains(buf.String(), "database unavailable") {
+		t.Fatalf("log output %q doesn't contain callback error", buf.String())
+	}
+	if strings.Contains(w.Body.String(), "database unavailable") {
+		t.Fatal("response body contains callback error")
+	}
+}
+
+func TestWebhookServer_RejectsBadRequests(t *testing.T) {
+	s := NewWebhookServer(webhookTestSecret)
+	s.MaxPayloadSize = 64
+	s.OnPush(func(ctx context.Context, payload *PushEventPayload) error {
+		t.Fatal("callback should not be called")
+		return nil
+	})
+
+	getReq := makeWebhookRequest(t, PushEventType, `{}`)
+	getReq.Method = "GET"
+	expect(t, http.StatusMethodNotAllowed, serveWebhook(s, getReq).Code, "GET")
+
+	badSig := makeWebhookRequest(t, PushEventType, `{}`)
+	badSig.Header.Set("X-Hub-Signature-256", "sha256=00")
+	expect(t, http.StatusUnauthorized, serveWebhook(s, badSig).Code, "bad signature")
+
+	noSig := makeWebhookRequest(t, PushEventType, `{}`)
+	noSig.Header.Del("X-Hub-Signature-256")
+	expect(t, http.StatusUnauthorized, serveWebhook(s, noSig).Code, "no signature")
+
+	noEvent := makeWebhookRequest(t, PushEventType, `{}`)
+	noEvent.Header.Del("X-Github-Event")
+	expect(t, http.StatusBadRequest, serveWebhook(s, noEvent).Code, "no event")
+
+	badJSON := makeWebhookRequest(t, PushEventType, `{"ref":`)
+	expect(t, http.StatusBadRequest, serveWebhook(s, badJSON).Code, "bad JSON")
+
+	tooLarge := makeWebhookRequest(t, PushEventType, `{"ref":"`+strings.Repeat("a", 64)+`"}`)
+	expect(t, http.StatusRequestEntityTooLarge, serveWebhook(s, tooLarge).Code, "too large")
+}
+
+func TestWebhookServer_RejectsDuplicateDeliveries(t *testing.T) {
+	s := NewWebhookServer(webhookTestSecret)
+	s.Deliveries = NewMemoryDeliveryStore(0)
+
+	fail := true
+	var calls int
+	s.OnPush(func(ctx context.Context, payload *PushEventPayload) error {
+		calls++
+		if fail {
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+
+	// failed deliveries are forgotten so they can be redelivered
+	expect(t, http.StatusInternalServerError, serveWebhook(s, makeDeliveryRequest(t, testDeliveryID)).Code, "failed")
+
+	fail = false
+	expect(t, http.StatusNoContent, serveWebhook(s, makeDeliveryRequest(t, testDeliveryID)).Code, "redelivered")
+	expect(t, http.StatusConflict, serveWebhook(s, makeDeliveryRequest(t, testDeliveryID)).Code, "duplicate")
+	expect(t, 2, calls, "calls")
+
+	// so are deliveries whose payload can't be decoded
+	badJSON := makeWebhookRequest(t, PushEventType, `{"ref":`)
+	badJSON.Header.Set("X-GitHub-Delivery", "f7a5b3c0-6a0f-11e7-8c4e-6e4a2e3c9b1d")
+	expect(t, http.StatusBadRequest, serveWebhook(s, badJSON).Code, "bad JSON")
+	expect(t, http.StatusNoContent, serveWebhook(s, makeDeliveryRequest(t, "f7a5b3c0-6a0f-11e7-8c4e-6e4a2e3c9b1d")).Code, "redelivered after bad JSON")
+	expect(t, 3, calls, "calls")
+
+	noID := makeDeliveryRequest(t, testDeliveryID)
+	noID.Header.Del("X-GitHub-Delivery")
+	expect(t, http.StatusBadRequest, serveWebhook(s, noID).Code, "no delivery ID")
+}